@@ -0,0 +1,67 @@
+package playht
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+)
+
+// VoicesAPI lists the available voices.
+type VoicesAPI interface {
+	GetVoices(ctx context.Context) ([]Voice, error)
+	GetClonedVoices(ctx context.Context) ([]ClonedVoice, error)
+}
+
+// ClonesAPI manages the cloned voices.
+type ClonesAPI interface {
+	CreateInstantVoiceCloneFromFile(ctx context.Context, cloneReq *CloneVoiceFileRequest) (*ClonedVoice, error)
+	CreateInstantVoiceCloneFromURL(ctx context.Context, cloneReq *CloneVoiceURLRequest) (*ClonedVoice, error)
+	DeleteClonedVoice(ctx context.Context, delReq *DeleteClonedVoiceRequest) (*DeleteClonedVoiceResp, error)
+}
+
+// JobsAPI manages the TTS jobs.
+type JobsAPI interface {
+	CreateTTSJob(ctx context.Context, createReq *CreateTTSJobReq) (*TTSJob, error)
+	GetTTSJob(ctx context.Context, id string) (*TTSJob, error)
+	WaitForTTSJob(ctx context.Context, id string, opts ...WaitOption) (*TTSJob, error)
+	GetTTSJobAudioStream(ctx context.Context, w io.Writer, id string) error
+	CreateTTSJobWithProgressStream(ctx context.Context, w io.Writer, createReq *CreateTTSJobReq) (string, error)
+	GetTTSJobProgressStream(ctx context.Context, w io.Writer, id string) error
+	TTSJobProgressEvents(ctx context.Context, id string, opts ...JobProgressOption) iter.Seq2[*JobProgressEvent, error]
+	CreateTTSJobWithProgressEvents(ctx context.Context, createReq *CreateTTSJobReq, opts ...JobProgressOption) iter.Seq2[*JobProgressEvent, error]
+	DownloadTTSJobAudio(ctx context.Context, job *TTSJob, w io.Writer, opts ...DownloadOption) (int64, error)
+	DownloadTTSJobAudioFile(ctx context.Context, job *TTSJob, path string, opts ...DownloadOption) (int64, error)
+}
+
+// StreamsAPI streams the TTS audio.
+// NOTE: TTSGrpcChunks is not part of the interface
+// as ChunkStream can only be created by Client.
+type StreamsAPI interface {
+	TTSStream(ctx context.Context, w io.Writer, createReq *CreateTTSStreamReq) error
+	TTSStreamReader(ctx context.Context, createReq *CreateTTSStreamReq) (io.ReadCloser, error)
+	TTSStreamURL(ctx context.Context, createReq *CreateTTSStreamReq) (*TTSStreamURL, error)
+	TTSGrpcStream(ctx context.Context, w io.Writer, req *pb.TtsRequest, opts ...GrpcStreamOption) error
+	TTSGrpcStreamReader(ctx context.Context, req *pb.TtsRequest, opts ...GrpcStreamOption) (io.ReadCloser, error)
+}
+
+// LeasesAPI creates the gRPC API leases.
+type LeasesAPI interface {
+	CreateLease(ctx context.Context, createReq *CreateLeaseReq) (*Lease, error)
+	RefreshLease(ctx context.Context, createReq *CreateLeaseReq) (*Lease, error)
+}
+
+// API is the PlayHT API implemented by Client.
+// Depend on it, or on the smaller interfaces it embeds,
+// to replace Client with a fake in tests.
+type API interface {
+	VoicesAPI
+	ClonesAPI
+	JobsAPI
+	StreamsAPI
+	LeasesAPI
+	Close() error
+}
+
+var _ API = (*Client)(nil)
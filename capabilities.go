@@ -0,0 +1,178 @@
+package playht
+
+import "slices"
+
+var (
+	allFormats   = []OutputFormat{Mp3, Wav, Ogg, Flac, Mulaw, Raw}
+	allQualities = []Quality{Draft, Low, Medium, High, Premium, Unspecified}
+	// httpFormats and httpQualities are supported by the engines
+	// which are not available via gRPC: Raw and Unspecified are gRPC only.
+	httpFormats   = []OutputFormat{Mp3, Wav, Ogg, Flac, Mulaw}
+	httpQualities = []Quality{Draft, Low, Medium, High, Premium}
+	allEmotions   = []Emotion{
+		FemaleHappy, FemaleSad, FemaleAngry, FemaleFearful, FemaleDisgust, FemaleSurprised,
+		MaleHappy, MaleSad, MaleAngry, MaleFearful, MaleDisgust, MaleSurprised,
+	}
+	allSampleRates = []int32{8000, 16000, 22050, 24000, 44100, 48000}
+	// mulawSampleRates are the sample rates supported by Mulaw format.
+	mulawSampleRates = []int32{8000}
+)
+
+// ParamRange is the inclusive range of the parameter values.
+type ParamRange struct {
+	Min float32
+	Max float32
+}
+
+// Contains returns true if v is in the range.
+func (r ParamRange) Contains(v float32) bool {
+	return v >= r.Min && v <= r.Max
+}
+
+var (
+	speedRange         = ParamRange{Min: 0.1, Max: 5}
+	temperatureRange   = ParamRange{Min: 0, Max: 2}
+	topPRange          = ParamRange{Min: 0, Max: 1}
+	voiceGuidanceRange = ParamRange{Min: 1, Max: 6}
+	styleGuidanceRange = ParamRange{Min: 1, Max: 30}
+	textGuidanceRange  = ParamRange{Min: 1, Max: 2}
+)
+
+// EngineCapabilities are the voice engine capabilities.
+type EngineCapabilities struct {
+	// Engine is the voice engine.
+	Engine VoiceEngine
+	// Formats are the supported output formats.
+	Formats []OutputFormat
+	// SampleRates are the supported sample rates.
+	// Use SampleRatesFor to get the sample rates supported by the given format.
+	SampleRates []int32
+	// Qualities are the supported quality levels.
+	Qualities []Quality
+	// Emotions are the supported emotions.
+	// It's empty if the engine doesn't support emotions.
+	Emotions []Emotion
+	// Speed is the speed range.
+	Speed ParamRange
+	// Temperature is the temperature range.
+	Temperature ParamRange
+	// TopP is the top_p range.
+	TopP ParamRange
+	// VoiceGuidance is the voice guidance range.
+	// It's nil if the engine doesn't support voice guidance.
+	VoiceGuidance *ParamRange
+	// StyleGuidance is the style guidance range.
+	// It's nil if the engine doesn't support style guidance.
+	StyleGuidance *ParamRange
+	// TextGuidance is the text guidance range.
+	// It's nil if the engine doesn't support text guidance.
+	TextGuidance *ParamRange
+	// HTTP is true if the engine is available via HTTP streaming.
+	HTTP bool
+	// GRPC is true if the engine is available via gRPC streaming.
+	GRPC bool
+	// Jobs is true if the engine is available via async TTS jobs.
+	Jobs bool
+}
+
+// SupportsEmotion returns true if the engine supports emotions.
+func (c EngineCapabilities) SupportsEmotion() bool {
+	return len(c.Emotions) > 0
+}
+
+// SampleRatesFor returns the sample rates supported by format.
+func (c EngineCapabilities) SampleRatesFor(format OutputFormat) []int32 {
+	if format == Mulaw {
+		return slices.DeleteFunc(slices.Clone(c.SampleRates), func(rate int32) bool {
+			return !slices.Contains(mulawSampleRates, rate)
+		})
+	}
+	return slices.Clone(c.SampleRates)
+}
+
+// clone returns a deep copy of the capabilities.
+func (c EngineCapabilities) clone() EngineCapabilities {
+	c.Formats = slices.Clone(c.Formats)
+	c.SampleRates = slices.Clone(c.SampleRates)
+	c.Qualities = slices.Clone(c.Qualities)
+	c.Emotions = slices.Clone(c.Emotions)
+	c.VoiceGuidance = clone(c.VoiceGuidance)
+	c.StyleGuidance = clone(c.StyleGuidance)
+	c.TextGuidance = clone(c.TextGuidance)
+	return c
+}
+
+// capabilities is the voice engine capability table.
+var capabilities = map[VoiceEngine]EngineCapabilities{
+	PlayHTv1: {
+		Engine:      PlayHTv1,
+		Formats:     httpFormats,
+		SampleRates: allSampleRates,
+		Qualities:   httpQualities,
+		Speed:       speedRange,
+		Temperature: temperatureRange,
+		TopP:        topPRange,
+		HTTP:        true,
+		Jobs:        true,
+	},
+	PlayHTv2: {
+		Engine:        PlayHTv2,
+		Formats:       allFormats,
+		SampleRates:   allSampleRates,
+		Qualities:     allQualities,
+		Emotions:      allEmotions,
+		Speed:         speedRange,
+		Temperature:   temperatureRange,
+		TopP:          topPRange,
+		VoiceGuidance: &voiceGuidanceRange,
+		StyleGuidance: &styleGuidanceRange,
+		TextGuidance:  &textGuidanceRange,
+		HTTP:          true,
+		GRPC:          true,
+		Jobs:          true,
+	},
+	// NOTE: turbo engine is only available via streaming.
+	PlayHTv2Turbo: {
+		Engine:        PlayHTv2Turbo,
+		Formats:       allFormats,
+		SampleRates:   allSampleRates,
+		Qualities:     allQualities,
+		Speed:         speedRange,
+		Temperature:   temperatureRange,
+		TopP:          topPRange,
+		VoiceGuidance: &voiceGuidanceRange,
+		TextGuidance:  &textGuidanceRange,
+		HTTP:          true,
+		GRPC:          true,
+	},
+}
+
+// anyCapabilities are the capabilities used when the voice engine is not known.
+// They allow everything any engine supports.
+var anyCapabilities = EngineCapabilities{
+	Formats:       allFormats,
+	SampleRates:   allSampleRates,
+	Qualities:     allQualities,
+	Emotions:      allEmotions,
+	Speed:         speedRange,
+	Temperature:   temperatureRange,
+	TopP:          topPRange,
+	VoiceGuidance: &voiceGuidanceRange,
+	StyleGuidance: &styleGuidanceRange,
+	TextGuidance:  &textGuidanceRange,
+}
+
+// Capabilities returns the capabilities of the voice engine.
+// It returns false if the engine is not known.
+func Capabilities(engine VoiceEngine) (EngineCapabilities, bool) {
+	caps, ok := capabilities[engine]
+	if !ok {
+		return EngineCapabilities{}, false
+	}
+	return caps.clone(), true
+}
+
+// VoiceEngines returns all the known voice engines.
+func VoiceEngines() []VoiceEngine {
+	return []VoiceEngine{PlayHTv1, PlayHTv2, PlayHTv2Turbo}
+}
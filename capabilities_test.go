@@ -0,0 +1,70 @@
+package playht
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range VoiceEngines() {
+		t.Run(engine.String(), func(t *testing.T) {
+			t.Parallel()
+			caps, ok := Capabilities(engine)
+			assert.True(t, ok)
+			assert.Equal(t, engine, caps.Engine)
+			assert.NotEmpty(t, caps.Formats)
+			assert.NotEmpty(t, caps.Qualities)
+
+			// NOTE: the capabilities must be kept in sync with the proto conversions
+			for _, format := range caps.Formats {
+				assert.NotNil(t, ToPbFormat(format), format)
+				assert.Equal(t, format, FromPbFormat(ToPbFormat(format)))
+			}
+			for _, quality := range caps.Qualities {
+				assert.NotNil(t, ToPbQuality(quality), quality)
+				assert.Equal(t, quality, FromPbQuality(ToPbQuality(quality)))
+			}
+		})
+	}
+
+	t.Run("details", func(t *testing.T) {
+		t.Parallel()
+		v2, _ := Capabilities(PlayHTv2)
+		assert.True(t, v2.SupportsEmotion())
+		assert.NotNil(t, v2.StyleGuidance)
+		assert.True(t, v2.GRPC)
+
+		turbo, _ := Capabilities(PlayHTv2Turbo)
+		assert.False(t, turbo.SupportsEmotion())
+		assert.Nil(t, turbo.StyleGuidance)
+		assert.False(t, turbo.Jobs)
+		assert.Equal(t, []int32{8000}, turbo.SampleRatesFor(Mulaw))
+
+		v1, _ := Capabilities(PlayHTv1)
+		assert.False(t, v1.GRPC)
+		assert.True(t, v1.HTTP)
+		// gRPC only formats and qualities are not supported without gRPC
+		assert.NotContains(t, v1.Formats, Raw)
+		assert.NotContains(t, v1.Qualities, Unspecified)
+		assert.Contains(t, v2.Formats, Raw)
+		assert.Contains(t, v2.Qualities, Unspecified)
+	})
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+		caps, _ := Capabilities(PlayHTv2)
+		caps.Formats[0] = "foo"
+		caps.VoiceGuidance.Max = 100
+
+		caps, _ = Capabilities(PlayHTv2)
+		assert.Equal(t, Mp3, caps.Formats[0])
+		assert.Equal(t, float32(6), caps.VoiceGuidance.Max)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, ok := Capabilities("foo")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,504 @@
+// Package cassette implements record/replay http.RoundTripper for deterministic tests.
+//
+// The cassette records the HTTP interactions to a JSON file and replays them
+// later without hitting the network. Plug it into the PlayHT client via:
+//
+//	cas, err := cassette.New("testdata/voices.json", cassette.WithMode(cassette.ModeAuto))
+//	httpClient := client.NewHTTP(client.WithHTTPClient(cas.Client()))
+//	c := playht.NewClient(playht.WithHTTPClient(httpClient))
+//	...
+//	err = cas.Save()
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/milosgajdos/go-playht"
+)
+
+// Redacted replaces the values of the redacted headers.
+const Redacted = "REDACTED"
+
+var (
+	// ErrNoInteraction is returned when no recorded interaction matches the request.
+	ErrNoInteraction = errors.New("cassette: no matching interaction")
+	// DefaultRedactHeaders are the headers redacted by default.
+	DefaultRedactHeaders = []string{"Authorization", playht.UserIDHeader}
+)
+
+// Mode is the cassette mode.
+type Mode int
+
+const (
+	// ModeReplay replays the recorded interactions.
+	ModeReplay Mode = iota
+	// ModeRecord records the interactions using the underlying transport.
+	ModeRecord
+	// ModeAuto replays the interactions if the cassette file exists
+	// and records them otherwise.
+	ModeAuto
+)
+
+// String implements fmt.Stringer.
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModeAuto:
+		return "auto"
+	default:
+		return "Mode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// Body is the recorded HTTP body.
+// It's encoded as JSON string if it's valid UTF-8
+// and as an object with base64 encoded data otherwise.
+type Body []byte
+
+type base64Body struct {
+	Base64 string `json:"base64"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(base64Body{Base64: base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var enc base64Body
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(enc.Base64)
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// Request is the recorded HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is the recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Interaction is the recorded HTTP request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// file is the cassette file layout.
+type file struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Options configure the cassette.
+type Options struct {
+	// Mode is the cassette mode.
+	Mode Mode
+	// Transport is used to send the requests when recording.
+	Transport http.RoundTripper
+	// RedactHeaders are the request and response headers
+	// whose values are redacted in the recorded interactions.
+	RedactHeaders []string
+}
+
+// Option is a cassette functional option.
+type Option func(*Options)
+
+// WithMode sets the cassette mode.
+func WithMode(mode Mode) Option {
+	return func(o *Options) {
+		o.Mode = mode
+	}
+}
+
+// WithTransport sets the transport used to send the requests when recording.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *Options) {
+		o.Transport = rt
+	}
+}
+
+// WithRedactHeaders redacts the given headers in addition to DefaultRedactHeaders.
+func WithRedactHeaders(headers ...string) Option {
+	return func(o *Options) {
+		o.RedactHeaders = append(o.RedactHeaders, headers...)
+	}
+}
+
+// Cassette is a record/replay http.RoundTripper.
+// Requests are matched by method, URL path and body. JSON bodies
+// are normalized and multipart bodies are compared part by part.
+// Each recorded interaction is replayed at most once, in the recorded order.
+type Cassette struct {
+	path      string
+	opts      Options
+	recording bool
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// New creates a new cassette stored at path and returns it.
+// The cassette file is loaded when replaying.
+func New(path string, opts ...Option) (*Cassette, error) {
+	options := Options{
+		Mode:          ModeReplay,
+		Transport:     http.DefaultTransport,
+		RedactHeaders: append([]string{}, DefaultRedactHeaders...),
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	c := &Cassette{
+		path: path,
+		opts: options,
+	}
+
+	switch options.Mode {
+	case ModeRecord:
+		c.recording = true
+		return c, nil
+	case ModeReplay, ModeAuto:
+		err := c.load()
+		if errors.Is(err, fs.ErrNotExist) && options.Mode == ModeAuto {
+			c.recording = true
+			return c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("cassette: invalid mode: %v", options.Mode)
+	}
+}
+
+func (c *Cassette) load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("cassette: decode %s: %w", c.path, err)
+	}
+	c.interactions = f.Interactions
+	c.used = make([]bool, len(f.Interactions))
+	return nil
+}
+
+// Recording returns true if the cassette records the interactions.
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// Client returns a new http.Client which uses the cassette as its transport.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Interactions returns the recorded interactions.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	interactions := make([]Interaction, 0, len(c.interactions))
+	for _, i := range c.interactions {
+		interactions = append(interactions, *i)
+	}
+	return interactions
+}
+
+// Save writes the recorded interactions to the cassette file.
+// Response bodies are recorded as they're read, so Save must be
+// called once all the response bodies have been consumed.
+// It's a noop when replaying.
+func (c *Cassette) Save() error {
+	if !c.recording {
+		return nil
+	}
+
+	c.mu.Lock()
+	data, err := json.MarshalIndent(file{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.recording {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	resp, err := c.opts.Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: c.redact(req.Header),
+			Body:   body,
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     c.redact(resp.Header),
+		},
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, i)
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+
+	// NOTE: the body is recorded as it's read rather than upfront
+	// so that streaming responses are passed through without buffering.
+	resp.Body = &recordBody{
+		ReadCloser: resp.Body,
+		cassette:   c,
+		resp:       &i.Response,
+	}
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key, err := normalize(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for idx, i := range c.interactions {
+		if c.used[idx] || !c.matches(i, req, key) {
+			continue
+		}
+		c.used[idx] = true
+
+		header := i.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		// the recorded body may be shorter than the original one
+		// if the client stopped reading the response early.
+		if header.Get("Content-Length") != "" {
+			header.Set("Content-Length", strconv.Itoa(len(i.Response.Body)))
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(i.Response.Body)),
+			ContentLength: int64(len(i.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.Path)
+}
+
+func (c *Cassette) matches(i *Interaction, req *http.Request, key string) bool {
+	if i.Request.Method != req.Method {
+		return false
+	}
+	u, err := url.Parse(i.Request.URL)
+	if err != nil || u.Path != req.URL.Path {
+		return false
+	}
+	recorded, err := normalize(i.Request.Header.Get("Content-Type"), i.Request.Body)
+	if err != nil {
+		return false
+	}
+	return recorded == key
+}
+
+// redact returns a copy of h with the redacted header values replaced.
+// The authorization scheme of the Authorization header is kept.
+func (c *Cassette) redact(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range c.opts.RedactHeaders {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		redacted := make([]string, 0, len(values))
+		for _, v := range values {
+			if scheme, _, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Bearer") {
+				redacted = append(redacted, scheme+" "+Redacted)
+				continue
+			}
+			redacted = append(redacted, Redacted)
+		}
+		h[http.CanonicalHeaderKey(name)] = redacted
+	}
+	return h
+}
+
+// recordBody records the response body as it's read.
+type recordBody struct {
+	io.ReadCloser
+	cassette *Cassette
+	resp     *Response
+}
+
+func (b *recordBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.cassette.mu.Lock()
+		b.resp.Body = append(b.resp.Body, p[:n]...)
+		b.cassette.mu.Unlock()
+	}
+	return n, err
+}
+
+// readBody reads the request body and replaces it
+// with a new reader so the request can be resent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Body.Close(); err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// part is the normalized multipart body part.
+type part struct {
+	Name        string `json:"name"`
+	FileName    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// normalize returns the normalized body used for matching the requests.
+// JSON bodies are re-encoded with sorted keys. Multipart bodies are stripped
+// of their random boundaries and their parts are sorted by name.
+// Other bodies are kept intact.
+func normalize(contentType string, body []byte) (string, error) {
+	if len(body) == 0 {
+		return "", nil
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		var parts []part
+		r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			p, err := r.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("cassette: read multipart body: %w", err)
+			}
+			data, err := io.ReadAll(p)
+			if err != nil {
+				return "", fmt.Errorf("cassette: read multipart body: %w", err)
+			}
+			parts = append(parts, part{
+				Name:        p.FormName(),
+				FileName:    p.FileName(),
+				ContentType: p.Header.Get("Content-Type"),
+				Body:        data,
+			})
+		}
+		// NOTE: the order of the form fields is not significant
+		slices.SortStableFunc(parts, func(a, b part) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		data, err := json.Marshal(parts)
+		return string(data), err
+	}
+
+	if json.Valid(body) {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+
+	return string(body), nil
+}
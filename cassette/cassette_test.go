@@ -0,0 +1,264 @@
+package cassette_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/milosgajdos/go-playht/cassette"
+	"github.com/milosgajdos/go-playht/client"
+	"github.com/milosgajdos/go-playht/playhttest"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	secretKey = "s3cr3t-key"
+	userID    = "user-1234"
+)
+
+func newClient(cas *cassette.Cassette, baseURL string) *playht.Client {
+	return playht.NewClient(
+		playht.WithBaseURL(baseURL),
+		playht.WithSecretKey(secretKey),
+		playht.WithUserID(userID),
+		playht.WithHTTPClient(client.NewHTTP(client.WithHTTPClient(cas.Client()))),
+	)
+}
+
+type result struct {
+	voices   []playht.Voice
+	audio    []byte
+	progress string
+	clone    *playht.ClonedVoice
+}
+
+func run(t *testing.T, c *playht.Client) result {
+	t.Helper()
+	ctx := context.Background()
+
+	voices, err := c.GetVoices(ctx)
+	assert.NoError(t, err)
+
+	audio := &bytes.Buffer{}
+	streamReq := &playht.CreateTTSStreamReq{Text: "hello", Voice: "voice", OutputFormat: playht.Mp3}
+	assert.NoError(t, c.TTSStream(ctx, audio, streamReq))
+
+	progress := &bytes.Buffer{}
+	jobReq := &playht.CreateTTSJobReq{Text: "hello", Voice: "voice", OutputFormat: playht.Wav}
+	_, err = c.CreateTTSJobWithProgressStream(ctx, progress, jobReq)
+	assert.NoError(t, err)
+
+	clone, err := c.CreateInstantVoiceCloneFromURL(ctx, &playht.CloneVoiceURLRequest{
+		SampleFileURL: "https://example.com/sample.wav",
+		VoiceName:     "clone",
+	})
+	assert.NoError(t, err)
+
+	return result{
+		voices:   voices,
+		audio:    audio.Bytes(),
+		progress: progress.String(),
+		clone:    clone,
+	}
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	srv := playhttest.NewServer(
+		playhttest.WithCredentials(secretKey, userID),
+		playhttest.WithJobDuration(0),
+	)
+	baseURL := srv.URL
+
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	assert.NoError(t, err)
+	assert.True(t, rec.Recording())
+
+	recorded := run(t, newClient(rec, baseURL))
+	assert.NoError(t, rec.Save())
+	srv.Close()
+
+	assert.NotEmpty(t, recorded.audio)
+	assert.Contains(t, recorded.progress, "completed")
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), secretKey)
+	assert.NotContains(t, string(data), userID)
+	assert.Contains(t, string(data), cassette.Redacted)
+
+	play, err := cassette.New(path, cassette.WithMode(cassette.ModeAuto))
+	assert.NoError(t, err)
+	assert.False(t, play.Recording())
+
+	replayed := run(t, newClient(play, baseURL))
+	assert.Equal(t, recorded, replayed)
+
+	// every interaction is replayed only once
+	_, err = newClient(play, baseURL).GetVoices(context.Background())
+	assert.ErrorIs(t, err, cassette.ErrNoInteraction)
+}
+
+func TestCassetteRedact(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	defer srv.Close()
+
+	cas, err := cassette.New(filepath.Join(t.TempDir(), "cassette.json"),
+		cassette.WithMode(cassette.ModeRecord),
+		cassette.WithRedactHeaders("X-Custom"),
+	)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/v2/voices", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+secretKey)
+	req.Header.Set(playht.UserIDHeader, userID)
+	req.Header.Set("X-Custom", "custom")
+
+	resp, err := cas.Client().Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	interactions := cas.Interactions()
+	if assert.Len(t, interactions, 1) {
+		header := interactions[0].Request.Header
+		assert.Equal(t, "Bearer "+cassette.Redacted, header.Get("Authorization"))
+		assert.Equal(t, cassette.Redacted, header.Get(playht.UserIDHeader))
+		assert.Equal(t, cassette.Redacted, header.Get("X-Custom"))
+	}
+	// the request sent over the wire must not be redacted
+	assert.Equal(t, "Bearer "+secretKey, req.Header.Get("Authorization"))
+}
+
+func TestCassetteMatch(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	assert.NoError(t, err)
+	resp, err := rec.Client().Post(srv.URL+"/v2/tts?format=json", "application/json",
+		strings.NewReader(`{"text": "hello", "voice": "voice", "speed": 1.0}`))
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, rec.Save())
+	srv.Close()
+
+	testCases := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		err    error
+	}{
+		{
+			name:   "normalized JSON body",
+			method: http.MethodPost,
+			url:    "http://example.com/v2/tts",
+			body:   `{"speed":1.0,"voice":"voice","text":"hello"}`,
+		},
+		{
+			name:   "different body",
+			method: http.MethodPost,
+			url:    "http://example.com/v2/tts",
+			body:   `{"text":"bye","voice":"voice","speed":1.0}`,
+			err:    cassette.ErrNoInteraction,
+		},
+		{
+			name:   "different path",
+			method: http.MethodPost,
+			url:    "http://example.com/v2/tts/stream",
+			body:   `{"text":"hello","voice":"voice","speed":1.0}`,
+			err:    cassette.ErrNoInteraction,
+		},
+		{
+			name:   "different method",
+			method: http.MethodPut,
+			url:    "http://example.com/v2/tts",
+			body:   `{"text":"hello","voice":"voice","speed":1.0}`,
+			err:    cassette.ErrNoInteraction,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			play, err := cassette.New(path)
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := play.RoundTrip(req)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewReplayMissing(t *testing.T) {
+	t.Parallel()
+
+	_, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	cas, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.WithMode(cassette.ModeAuto))
+	assert.NoError(t, err)
+	assert.True(t, cas.Recording())
+}
+
+func multipartBody(t *testing.T, fields ...string) (string, *bytes.Buffer) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for i := 0; i < len(fields); i += 2 {
+		assert.NoError(t, w.WriteField(fields[i], fields[i+1]))
+	}
+	assert.NoError(t, w.Close())
+	return w.FormDataContentType(), body
+}
+
+func TestCassetteMultipart(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	assert.NoError(t, err)
+	ct, body := multipartBody(t, "voice_name", "clone", "sample_file_url", "https://example.com/sample.wav")
+	resp, err := rec.Client().Post(srv.URL+"/v2/cloned-voices/instant", ct, body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, rec.Save())
+	srv.Close()
+
+	play, err := cassette.New(path)
+	assert.NoError(t, err)
+
+	// different boundary and part order
+	ct, body = multipartBody(t, "sample_file_url", "https://example.com/sample.wav", "voice_name", "clone")
+	resp, err = play.Client().Post(srv.URL+"/v2/cloned-voices/instant", ct, body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.NoError(t, resp.Body.Close())
+}
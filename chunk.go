@@ -0,0 +1,289 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+)
+
+// Chunk is a single gRPC TTS stream chunk.
+type Chunk struct {
+	// Sequence is the chunk sequence number.
+	Sequence int32
+	// ID is the stream ID.
+	ID string
+	// Data contains the chunk audio bytes.
+	Data []byte
+	// Status is the stream status, if any.
+	Status *pb.Status
+}
+
+// ChunkStream iterates over the gRPC TTS stream chunks.
+//
+//	for chunks.Next() {
+//		chunk := chunks.Chunk()
+//		// ...
+//	}
+//	if err := chunks.Err(); err != nil {
+//		// ...
+//	}
+type ChunkStream struct {
+	stream pb.Tts_TtsClient
+	cancel context.CancelFunc
+	opts   GrpcStreamOptions
+	chunk  *Chunk
+	// queue contains the chunks ready to be returned.
+	queue []*Chunk
+	// pending contains the out of order chunks
+	// waiting for the missing chunks to arrive.
+	pending map[int32]*Chunk
+	// held contains the status chunks received while
+	// the out of order chunks are pending.
+	held []*Chunk
+	// next is the next expected sequence.
+	next    int32
+	started bool
+	// seq is the last sequence received.
+	seq int32
+	// end is the error that ends the stream
+	// once all the queued chunks are returned.
+	end error
+	err error
+	// received is set once the first chunk is received.
+	received bool
+	// reopen reopens the stream with a new lease.
+	// It's only set if the lease was obtained from the LeaseManager.
+	reopen func() (pb.Tts_TtsClient, error)
+}
+
+// TTSGrpcChunks creates a new TTS stream over gRPC and returns the stream chunk iterator
+// as soon as the stream has been opened. Stream status is handled the same way as in TTSGrpcStream.
+// Sequence gaps and out of order chunks are only detected when WithSequenceCheck
+// or WithReorder option is used. The returned ChunkStream must be closed.
+// gRPC errors are returned as *GrpcError. If the lease was obtained from the client
+// LeaseManager and the stream fails with ErrLeaseExpired before receiving any chunk,
+// the lease is refreshed and the stream is reopened once.
+// It returns ErrNoGrpcConn if the client has no gRPC connection; see WithGRPCClient.
+func (c *Client) TTSGrpcChunks(ctx context.Context, req *pb.TtsRequest, opts ...GrpcStreamOption) (*ChunkStream, error) {
+	if c.opts.GRPC == nil {
+		return nil, ErrNoGrpcConn
+	}
+
+	options := GrpcStreamOptions{}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	managed := len(req.GetLease()) == 0
+	req, err := c.withLease(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ttsc := pb.NewTtsClient(c.opts.GRPC)
+	tts, err := ttsc.Tts(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, NewGrpcError(err, nil)
+	}
+
+	s := newChunkStream(tts, cancel, options)
+	if managed {
+		s.reopen = func() (pb.Tts_TtsClient, error) {
+			c.leases.Invalidate()
+			req, err := c.withLease(ctx, &pb.TtsRequest{Params: req.GetParams()})
+			if err != nil {
+				return nil, err
+			}
+			tts, err := ttsc.Tts(ctx, req)
+			if err != nil {
+				return nil, NewGrpcError(err, nil)
+			}
+			return tts, nil
+		}
+	}
+
+	return s, nil
+}
+
+func newChunkStream(stream pb.Tts_TtsClient, cancel context.CancelFunc, opts GrpcStreamOptions) *ChunkStream {
+	return &ChunkStream{
+		stream:  stream,
+		cancel:  cancel,
+		opts:    opts,
+		pending: make(map[int32]*Chunk),
+	}
+}
+
+// Next advances the stream to the next chunk which is then available via Chunk.
+// It returns false when the stream ends or fails; Err returns the error, if any.
+func (s *ChunkStream) Next() bool {
+	for s.err == nil {
+		if len(s.queue) > 0 {
+			s.chunk, s.queue = s.queue[0], s.queue[1:]
+			return true
+		}
+		if s.end != nil {
+			s.err = s.end
+			if s.end == io.EOF && len(s.pending) > 0 {
+				s.err = &SequenceError{
+					Expected: s.next,
+					Got:      slices.Min(slices.Collect(maps.Keys(s.pending))),
+				}
+			}
+			break
+		}
+		s.recv()
+	}
+	s.chunk = nil
+	return false
+}
+
+// Chunk returns the current chunk.
+func (s *ChunkStream) Chunk() *Chunk {
+	return s.chunk
+}
+
+// Err returns the error which ended the stream.
+// It returns nil if the stream completed successfully.
+func (s *ChunkStream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close cancels the stream.
+func (s *ChunkStream) Close() error {
+	s.cancel()
+	return nil
+}
+
+// recv receives the next chunk from the stream.
+func (s *ChunkStream) recv() {
+	resp, err := s.stream.Recv()
+	if err != nil {
+		if err != io.EOF {
+			err = NewGrpcError(err, s.stream.Trailer())
+		}
+		if !s.received && s.reopen != nil && errors.Is(err, ErrLeaseExpired) {
+			reopen := s.reopen
+			s.reopen = nil
+			stream, rerr := reopen()
+			if rerr == nil {
+				s.stream = stream
+				return
+			}
+			err = rerr
+		}
+		s.end = err
+		return
+	}
+	s.received = true
+
+	chunk := &Chunk{
+		Sequence: resp.Sequence,
+		ID:       resp.Id,
+		Data:     resp.Data,
+		Status:   resp.Status,
+	}
+	if chunk.Sequence != 0 || len(chunk.Data) > 0 {
+		s.seq = chunk.Sequence
+	}
+
+	// NOTE: status chunks are held back until the pending chunks
+	// arrive so the stream doesn't end before they're returned.
+	if s.opts.SequenceCheck && len(chunk.Data) == 0 && len(s.pending) > 0 {
+		s.held = append(s.held, chunk)
+		return
+	}
+
+	s.push(chunk)
+	s.handleStatus(chunk)
+}
+
+// handleStatus handles the chunk status.
+// The status does not override the error which already ends the stream.
+func (s *ChunkStream) handleStatus(chunk *Chunk) {
+	if err := s.status(chunk); err != nil && s.end == nil {
+		s.end = err
+	}
+}
+
+// push queues the chunk.
+// If the sequence check is enabled the out of order chunks
+// are held back until the missing chunks arrive.
+func (s *ChunkStream) push(chunk *Chunk) {
+	// NOTE: chunks without audio only carry stream status
+	if !s.opts.SequenceCheck || len(chunk.Data) == 0 {
+		s.queue = append(s.queue, chunk)
+		return
+	}
+
+	if !s.started {
+		s.started = true
+		s.next = chunk.Sequence
+	}
+
+	_, dup := s.pending[chunk.Sequence]
+	switch {
+	case chunk.Sequence == s.next:
+		s.queue = append(s.queue, chunk)
+		s.next++
+		for {
+			c, ok := s.pending[s.next]
+			if !ok {
+				break
+			}
+			delete(s.pending, s.next)
+			s.queue = append(s.queue, c)
+			s.next++
+		}
+		if len(s.pending) == 0 {
+			held := s.held
+			s.held = nil
+			for _, c := range held {
+				s.queue = append(s.queue, c)
+				s.handleStatus(c)
+			}
+		}
+	case chunk.Sequence > s.next && !dup && len(s.pending) < s.opts.ReorderWindow:
+		s.pending[chunk.Sequence] = chunk
+	default:
+		s.end = &SequenceError{
+			Expected: s.next,
+			Got:      chunk.Sequence,
+		}
+	}
+}
+
+// status handles the chunk status.
+// It returns io.EOF when the stream has completed or
+// *StreamStatusError if the stream reported an error.
+func (s *ChunkStream) status(chunk *Chunk) error {
+	status := chunk.Status
+	if status == nil {
+		return nil
+	}
+	if s.opts.StatusFunc != nil {
+		s.opts.StatusFunc(s.seq, status)
+	}
+
+	switch status.Code {
+	case pb.Code_CODE_COMPLETE:
+		return io.EOF
+	case pb.Code_CODE_ERROR, pb.Code_CODE_CANCELED:
+		return &StreamStatusError{
+			Code:     status.Code,
+			Messages: status.Message,
+			Sequence: s.seq,
+		}
+	default:
+		return nil
+	}
+}
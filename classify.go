@@ -0,0 +1,153 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/milosgajdos/go-playht/request"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsRateLimited returns true if err was caused by exceeding the API rate limit.
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var rateErr ErrRateLimit
+	if errors.As(err, &rateErr) {
+		return true
+	}
+	var rateErrPtr *ErrRateLimit
+	if errors.As(err, &rateErrPtr) {
+		return true
+	}
+	if code, ok := httpStatus(err); ok {
+		return code == http.StatusTooManyRequests
+	}
+	if code, ok := grpcCode(err); ok {
+		return code == codes.ResourceExhausted
+	}
+	return false
+}
+
+// IsAuthError returns true if err was caused by invalid or insufficient credentials.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrForbidden) {
+		return true
+	}
+	if code, ok := httpStatus(err); ok {
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+	if code, ok := grpcCode(err); ok {
+		return code == codes.Unauthenticated || code == codes.PermissionDenied
+	}
+	return false
+}
+
+// IsTimeout returns true if err was caused by a timeout.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if code, ok := grpcCode(err); ok {
+		return code == codes.DeadlineExceeded
+	}
+	return false
+}
+
+// IsCanceled returns true if err was caused by canceling the request.
+func IsCanceled(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	if code, ok := grpcCode(err); ok {
+		return code == codes.Canceled
+	}
+	return false
+}
+
+// IsRetryable returns true if the request which failed with err might succeed if retried.
+// Errors caused by canceling the request or by exceeding the request context deadline
+// are not retryable as retrying them with the same context is bound to fail.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsRateLimited(err) {
+		return true
+	}
+
+	var statusErr *StreamStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == pb.Code_CODE_ERROR
+	}
+	var internalErr ErrInternal
+	if errors.As(err, &internalErr) {
+		return true
+	}
+	if code, ok := httpStatus(err); ok {
+		return code == http.StatusRequestTimeout || code >= http.StatusInternalServerError
+	}
+	if code, ok := grpcCode(err); ok {
+		switch code {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// httpStatus returns the HTTP status code of the API error.
+func httpStatus(err error) (int, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode != 0 {
+		return apiErr.StatusCode, true
+	}
+	var respErr *request.Error
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode, true
+	}
+	return 0, false
+}
+
+// grpcCode returns the gRPC status code of the gRPC error.
+func grpcCode(err error) (codes.Code, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return codes.OK, false
+	}
+	return grpcErr.GRPCStatus().Code(), true
+}
@@ -0,0 +1,70 @@
+package playht
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/milosgajdos/go-playht/request"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		err         error
+		retryable   bool
+		rateLimited bool
+		auth        bool
+		timeout     bool
+		canceled    bool
+	}{
+		{name: "nil"},
+		{name: "canceled", err: fmt.Errorf("wrapped: %w", context.Canceled), canceled: true},
+		{name: "deadline", err: context.DeadlineExceeded, timeout: true},
+		{name: "net timeout", err: &net.OpError{Op: "read", Err: timeoutError{}}, retryable: true, timeout: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, retryable: true},
+		{name: "api rate limit", err: &APIError{StatusCode: http.StatusTooManyRequests}, retryable: true, rateLimited: true},
+		{name: "rate limit body", err: &APIError{RateLimit: &ErrRateLimit{Message: "Rate limit exceeded"}}, retryable: true, rateLimited: true},
+		{name: "api unauthorized", err: &APIError{StatusCode: http.StatusUnauthorized}, auth: true},
+		{name: "api forbidden", err: &APIError{StatusCode: http.StatusForbidden}, auth: true},
+		{name: "api bad request", err: &APIError{StatusCode: http.StatusBadRequest}},
+		{name: "api internal", err: &APIError{StatusCode: http.StatusBadGateway}, retryable: true},
+		{name: "internal", err: ErrInternal{Message: "internal"}, retryable: true},
+		{name: "response error", err: &request.Error{StatusCode: http.StatusServiceUnavailable}, retryable: true},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "unavailable"), retryable: true},
+		{name: "grpc exhausted", err: status.Error(codes.ResourceExhausted, "exhausted"), retryable: true, rateLimited: true},
+		{name: "grpc unauthenticated", err: status.Error(codes.Unauthenticated, "unauthenticated"), auth: true},
+		{name: "grpc permission", err: status.Error(codes.PermissionDenied, "denied"), auth: true},
+		{name: "grpc invalid", err: status.Error(codes.InvalidArgument, "invalid")},
+		{name: "grpc canceled", err: status.Error(codes.Canceled, "canceled"), canceled: true},
+		{name: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "deadline"), timeout: true},
+		{name: "stream error", err: &StreamStatusError{Code: pb.Code_CODE_ERROR}, retryable: true},
+		{name: "stream canceled", err: &StreamStatusError{Code: pb.Code_CODE_CANCELED}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.retryable, IsRetryable(tc.err), "retryable")
+			assert.Equal(t, tc.rateLimited, IsRateLimited(tc.err), "rate limited")
+			assert.Equal(t, tc.auth, IsAuthError(tc.err), "auth")
+			assert.Equal(t, tc.timeout, IsTimeout(tc.err), "timeout")
+			assert.Equal(t, tc.canceled, IsCanceled(tc.err), "canceled")
+		})
+	}
+}
@@ -9,12 +9,14 @@ import (
 type HTTP struct {
 	client  *http.Client
 	limiter Limiter
+	retry   *RetryPolicy
 }
 
 // HTTPOptions configure the HTTP client.
 type HTTPOptions struct {
 	HTTPClient *http.Client
 	Limiter    Limiter
+	Retry      *RetryPolicy
 }
 
 // HTTPOption is HTTP client functional option.
@@ -52,11 +54,21 @@ func NewHTTP(opts ...HTTPOption) *HTTP {
 	return &HTTP{
 		client:  options.HTTPClient,
 		limiter: options.Limiter,
+		retry:   options.Retry,
 	}
 }
 
 // Do dispatches the HTTP request to the remote endpoint.
+// If the retry policy is set, the failed requests which are safe
+// to retry are retried. Every retry passes through the rate limiter.
 func (h *HTTP) Do(req *http.Request) (*http.Response, error) {
+	if h.retry != nil && retryable(req) {
+		return h.doRetry(req)
+	}
+	return h.do(req)
+}
+
+func (h *HTTP) do(req *http.Request) (*http.Response, error) {
 	if h.limiter != nil {
 		err := h.limiter.Wait(req.Context()) // This is a blocking call which honors the rate limit
 		if err != nil {
@@ -83,3 +95,10 @@ func WithLimiter(l Limiter) HTTPOption {
 		o.Limiter = l
 	}
 }
+
+// WithRetry sets the retry policy to p.
+func WithRetry(p RetryPolicy) HTTPOption {
+	return func(o *HTTPOptions) {
+		o.Retry = &p
+	}
+}
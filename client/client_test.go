@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingLimiter struct {
+	calls atomic.Int32
+}
+
+func (l *countingLimiter) Wait(context.Context) error {
+	l.calls.Add(1)
+	return nil
+}
+
+func newTestRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 2 * time.Millisecond
+	return p
+}
+
+func TestHTTPRetry(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(failures int32, reqs *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if reqs.Add(1) <= failures {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write(body)
+		}))
+	}
+
+	t.Run("idempotent", func(t *testing.T) {
+		t.Parallel()
+		var reqs atomic.Int32
+		srv := newServer(2, &reqs)
+		defer srv.Close()
+
+		limiter := &countingLimiter{}
+		c := NewHTTP(WithRetry(newTestRetryPolicy()), WithLimiter(limiter))
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		assert.NoError(t, err)
+		resp, err := c.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), reqs.Load())
+		assert.Equal(t, int32(3), limiter.calls.Load())
+	})
+	t.Run("max retries", func(t *testing.T) {
+		t.Parallel()
+		var reqs atomic.Int32
+		srv := newServer(10, &reqs)
+		defer srv.Close()
+
+		p := newTestRetryPolicy()
+		p.MaxRetries = 2
+		c := NewHTTP(WithRetry(p))
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		assert.NoError(t, err)
+		resp, err := c.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(3), reqs.Load())
+	})
+	t.Run("non idempotent", func(t *testing.T) {
+		t.Parallel()
+		var reqs atomic.Int32
+		srv := newServer(1, &reqs)
+		defer srv.Close()
+
+		c := NewHTTP(WithRetry(newTestRetryPolicy()))
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("body"))
+		assert.NoError(t, err)
+		resp, err := c.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(1), reqs.Load())
+	})
+	t.Run("retry safe", func(t *testing.T) {
+		t.Parallel()
+		var reqs atomic.Int32
+		srv := newServer(1, &reqs)
+		defer srv.Close()
+
+		c := NewHTTP(WithRetry(newTestRetryPolicy()))
+		ctx := RetrySafe(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader("body"))
+		assert.NoError(t, err)
+		resp, err := c.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "body", string(body))
+		assert.Equal(t, int32(2), reqs.Load())
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, time.Duration(0), retryAfter(""))
+	assert.Equal(t, time.Duration(0), retryAfter("foo"))
+	assert.Equal(t, time.Duration(0), retryAfter("-1"))
+	assert.Equal(t, 2*time.Second, retryAfter("2"))
+	d := retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, float64(time.Minute), float64(d), float64(2*time.Second))
+
+	p := newTestRetryPolicy()
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	assert.Equal(t, time.Second, p.backoff(0, resp))
+	assert.LessOrEqual(t, p.backoff(5, nil), p.MaxBackoff)
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default maximum number of request retries.
+	DefaultMaxRetries = 3
+	// DefaultMinBackoff is the default initial retry backoff.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the default maximum retry backoff.
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures retrying of the failed requests.
+// Only idempotent requests and requests whose context
+// has been marked via RetrySafe are retried.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries.
+	MaxRetries int
+	// MinBackoff is the initial backoff.
+	// The backoff doubles with every retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum backoff.
+	MaxBackoff time.Duration
+	// Statuses are the response status codes which are retried.
+	Statuses []int
+}
+
+// DefaultRetryPolicy returns the default retry policy.
+// It retries rate limited requests and requests
+// which failed with transient server errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		Statuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+type retrySafeKey struct{}
+
+// RetrySafe returns a copy of ctx which marks the requests
+// created with it as safe to retry regardless of their method.
+func RetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+// retryable returns true if req can be retried.
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	safe, _ := req.Context().Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// shouldRetry returns true if the request which returned resp and err should be retried.
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return slices.Contains(p.Statuses, resp.StatusCode)
+}
+
+// backoff returns the delay before the given retry attempt.
+// Retry-After response header takes precedence if it requests a longer delay.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	delay := p.MinBackoff << attempt
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay > 0 {
+		// NOTE: jitter prevents many clients retrying in lockstep
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+
+	if resp != nil {
+		if after := retryAfter(resp.Header.Get("Retry-After")); after > delay {
+			delay = after
+		}
+	}
+	return delay
+}
+
+// retryAfter parses the Retry-After header value.
+// It returns zero if the value is empty or malformed.
+func retryAfter(val string) time.Duration {
+	if val == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(val); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// doRetry sends req and retries it according to the retry policy.
+func (h *HTTP) doRetry(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := h.do(req)
+		if attempt >= h.retry.MaxRetries || !h.retry.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := h.retry.backoff(attempt, resp)
+		if resp != nil {
+			// NOTE: draining the body lets the connection be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Command playht-emulator runs a local PlayHT API emulator.
+//
+// It serves the PlayHT HTTP API and the gRPC playht.v1.Tts service.
+// The generated audio is deterministic and all the state is kept in memory.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/milosgajdos/go-playht/playhttest"
+	pb "github.com/milosgajdos/go-playht/proto"
+	"google.golang.org/grpc"
+)
+
+var (
+	httpAddr      string
+	grpcAddr      string
+	grpcAdvertise string
+	secretKey     string
+	userID        string
+	leaseKey      string
+	leaseDuration time.Duration
+	jobDuration   time.Duration
+	runeDuration  time.Duration
+	chunkSize     int
+	latency       time.Duration
+)
+
+func init() {
+	flag.StringVar(&httpAddr, "http-addr", "localhost:8080", "HTTP API listen address")
+	flag.StringVar(&grpcAddr, "grpc-addr", "localhost:8081", "gRPC API listen address")
+	flag.StringVar(&grpcAdvertise, "grpc-advertise", "", "gRPC address advertised in the lease metadata (default: gRPC listen address)")
+	flag.StringVar(&secretKey, "secret-key", os.Getenv("PLAYHT_SECRET_KEY"), "required API secret key; not checked if empty")
+	flag.StringVar(&userID, "user-id", os.Getenv("PLAYHT_USER_ID"), "required API user ID; not checked if empty")
+	flag.StringVar(&leaseKey, "lease-key", string(playhttest.DefaultLeaseKey), "key used to sign the leases")
+	flag.DurationVar(&leaseDuration, "lease-duration", playhttest.DefaultLeaseDuration, "duration of the issued leases")
+	flag.DurationVar(&jobDuration, "job-duration", 2*time.Second, "time it takes the TTS jobs to complete")
+	flag.DurationVar(&runeDuration, "rune-duration", playhttest.DefaultRuneDuration, "audio duration generated per text rune")
+	flag.IntVar(&chunkSize, "chunk-size", playhttest.DefaultChunkSize, "size of the streamed audio chunks")
+	flag.DurationVar(&latency, "grpc-latency", 0, "delay before sending each gRPC stream response")
+}
+
+func main() {
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	grpcLis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("failed listening on %s: %v", grpcAddr, err)
+	}
+	if grpcAdvertise == "" {
+		grpcAdvertise = grpcLis.Addr().String()
+	}
+
+	httpLis, err := net.Listen("tcp", httpAddr)
+	if err != nil {
+		log.Fatalf("failed listening on %s: %v", httpAddr, err)
+	}
+
+	handler := playhttest.NewHandler(
+		playhttest.WithCredentials(secretKey, userID),
+		playhttest.WithLeaseKey([]byte(leaseKey)),
+		playhttest.WithLeaseDuration(leaseDuration),
+		playhttest.WithLeaseMetadata(playht.LeaseMetadata{
+			UserID:                  userID,
+			InferenceAddress:        grpcAdvertise,
+			PremiumInferenceAddress: grpcAdvertise,
+		}),
+		playhttest.WithJobDuration(jobDuration),
+		playhttest.WithRuneDuration(runeDuration),
+		playhttest.WithChunkSize(chunkSize),
+	)
+	httpSrv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	grpcSrv := grpc.NewServer()
+	pb.RegisterTtsServer(grpcSrv, playhttest.NewGrpcServer(
+		playhttest.WithGrpcLeaseKey([]byte(leaseKey)),
+		playhttest.WithGrpcRuneDuration(runeDuration),
+		playhttest.WithGrpcChunkSize(chunkSize),
+		playhttest.WithGrpcLatency(latency),
+	))
+
+	errc := make(chan error, 2)
+	go func() {
+		log.Printf("serving HTTP API on %s", httpLis.Addr())
+		if err := httpSrv.Serve(httpLis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errc <- err
+		}
+	}()
+	go func() {
+		log.Printf("serving gRPC API on %s", grpcLis.Addr())
+		if err := grpcSrv.Serve(grpcLis); err != nil {
+			errc <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Print("shutting down")
+	case err := <-errc:
+		log.Printf("server failed: %v", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed shutting down HTTP server: %v", err)
+	}
+	grpcSrv.GracefulStop()
+}
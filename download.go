@@ -0,0 +1,226 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milosgajdos/go-playht/request"
+)
+
+const (
+	// DefaultDownloadRetries is the default maximum number
+	// of retries of the interrupted audio download.
+	DefaultDownloadRetries = 3
+	// DefaultDownloadRetryDelay is the default delay
+	// before resuming the interrupted audio download.
+	DefaultDownloadRetryDelay = time.Second
+)
+
+var (
+	// ErrNoJobOutput is returned when the TTS job has no output audio URL.
+	ErrNoJobOutput = errors.New("job has no output")
+	// ErrSizeMismatch is returned when the downloaded audio size doesn't match
+	// the TTS job output size or the resumed download starts at a wrong offset.
+	ErrSizeMismatch = errors.New("audio size mismatch")
+)
+
+// DownloadOptions configure the TTS job audio download.
+type DownloadOptions struct {
+	// Offset is the byte offset the download starts from.
+	// It's used to resume the previously interrupted download.
+	Offset int64
+	// MaxRetries is the maximum number of consecutive
+	// retries of the interrupted download.
+	MaxRetries int
+	// RetryDelay is the delay before resuming the interrupted download.
+	RetryDelay time.Duration
+}
+
+// DownloadOption is a TTS job audio download functional option.
+type DownloadOption func(*DownloadOptions)
+
+// WithDownloadOffset sets the byte offset the download starts from.
+func WithDownloadOffset(offset int64) DownloadOption {
+	return func(o *DownloadOptions) {
+		o.Offset = offset
+	}
+}
+
+// WithDownloadRetries sets the maximum number of consecutive
+// download retries and the delay before each retry.
+func WithDownloadRetries(n int, delay time.Duration) DownloadOption {
+	return func(o *DownloadOptions) {
+		o.MaxRetries = n
+		o.RetryDelay = delay
+	}
+}
+
+// DownloadTTSJobAudio downloads the completed TTS job audio into w and returns the number of bytes written.
+// Unlike GetTTSJobAudioStream it works with any job OutputFormat as it fetches the audio from the job output URL.
+// Interrupted downloads are resumed via HTTP Range requests. If w implements io.WriterAt the audio is written
+// at the offset it was downloaded from. The downloaded size is verified against the job output size.
+// Error responses are returned as *APIError; the retryable ones are retried.
+func (c *Client) DownloadTTSJobAudio(ctx context.Context, job *TTSJob, w io.Writer, opts ...DownloadOption) (int64, error) {
+	options := DownloadOptions{
+		MaxRetries: DefaultDownloadRetries,
+		RetryDelay: DefaultDownloadRetryDelay,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	if job.Output.URL == "" {
+		return 0, fmt.Errorf("%w: %s", ErrNoJobOutput, job.ID)
+	}
+
+	dst := w
+	if wa, ok := w.(io.WriterAt); ok {
+		dst = io.NewOffsetWriter(wa, options.Offset)
+	}
+
+	offset := options.Offset
+	retries := 0
+	for {
+		n, retry, err := c.downloadRange(ctx, job.Output.URL, offset, dst)
+		offset += n
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return offset - options.Offset, ctx.Err()
+		}
+		if n > 0 {
+			retries = 0
+		}
+		if !retry || retries >= options.MaxRetries {
+			return offset - options.Offset, err
+		}
+		retries++
+
+		select {
+		case <-ctx.Done():
+			return offset - options.Offset, ctx.Err()
+		case <-time.After(options.RetryDelay):
+		}
+	}
+
+	if size := int64(job.Output.Size); size > 0 && offset != size {
+		return offset - options.Offset, fmt.Errorf("%w: expected %d bytes, got %d", ErrSizeMismatch, size, offset)
+	}
+
+	return offset - options.Offset, nil
+}
+
+// DownloadTTSJobAudioFile downloads the completed TTS job audio into the file at path.
+// If the file already exists, the download resumes from the end of the file.
+func (c *Client) DownloadTTSJobAudioFile(ctx context.Context, job *TTSJob, path string, opts ...DownloadOption) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	opts = append(opts, WithDownloadOffset(info.Size()))
+	n, err := c.DownloadTTSJobAudio(ctx, job, f, opts...)
+	if err != nil {
+		return n, err
+	}
+	return n, f.Close()
+}
+
+// downloadRange downloads the audio at audioURL starting from offset into w.
+// It returns the number of bytes written and whether the download can be resumed on error.
+func (c *Client) downloadRange(ctx context.Context, audioURL string, offset int64, w io.Writer) (int64, bool, error) {
+	u, err := url.Parse(audioURL)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var options []request.HTTPOption
+	// NOTE: the output URL is usually not hosted by the API
+	// so we must not leak the API credentials to it.
+	if base, err := url.Parse(c.opts.BaseURL); err == nil && base.Host == u.Host {
+		options = append(options,
+			request.WithAuthSecret(c.opts.SecretKey),
+			request.WithSetHeader(UserIDHeader, c.opts.UserID),
+		)
+	}
+	if offset > 0 {
+		options = append(options, request.WithSetHeader("Range", fmt.Sprintf("bytes=%d-", offset)))
+	}
+
+	req, err := request.NewHTTP(ctx, http.MethodGet, u.String(), nil, options...)
+	if err != nil {
+		return 0, false, err
+	}
+
+	resp, err := request.Do[*APIError](c.opts.HTTPClient, req)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			// there is nothing left to download
+			if apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+				return 0, false, nil
+			}
+			return 0, IsRetryable(err), err
+		}
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// NOTE: the server ignored the range so we skip the bytes we already have
+		if offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+				return 0, true, err
+			}
+		}
+	case http.StatusPartialContent:
+		start, err := contentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, false, err
+		}
+		if start != offset {
+			return 0, false, fmt.Errorf("%w: requested offset %d, got %d", ErrSizeMismatch, offset, start)
+		}
+	default:
+		return 0, false, &request.Error{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		}
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	return n, true, err
+}
+
+// contentRangeStart returns the first byte position of the Content-Range header value.
+func contentRangeStart(contentRange string) (int64, error) {
+	rng, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range: %q", contentRange)
+	}
+	first, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid Content-Range: %q", contentRange)
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range: %q", contentRange)
+	}
+	return start, nil
+}
@@ -0,0 +1,169 @@
+package playht
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/go-playht/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadTTSJobAudio(t *testing.T) {
+	t.Parallel()
+
+	audio := bytes.Repeat([]byte("audio"), 1000)
+
+	newServer := func(interrupt bool) *httptest.Server {
+		var reqs atomic.Int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			if interrupt && reqs.Add(1) == 1 {
+				// NOTE: the connection is closed before the whole audio is sent
+				w.Header().Set("Content-Length", strconv.Itoa(len(audio)))
+				_, _ = w.Write(audio[:len(audio)/2])
+				return
+			}
+			http.ServeContent(w, r, "audio.wav", time.Time{}, bytes.NewReader(audio))
+		}))
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		t.Parallel()
+		srv := newServer(true)
+		defer srv.Close()
+
+		c := NewClient(WithSecretKey("secret"))
+		defer c.Close()
+
+		job := &TTSJob{ID: "job"}
+		job.Output.URL = srv.URL + "/audio.wav"
+		job.Output.Size = len(audio)
+
+		var buf bytes.Buffer
+		n, err := c.DownloadTTSJobAudio(context.Background(), job, &buf, WithDownloadRetries(1, time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(audio)), n)
+		assert.Equal(t, audio, buf.Bytes())
+	})
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		srv := newServer(false)
+		defer srv.Close()
+
+		c := NewClient()
+		defer c.Close()
+
+		job := &TTSJob{ID: "job"}
+		job.Output.URL = srv.URL + "/audio.wav"
+		job.Output.Size = len(audio)
+
+		path := filepath.Join(t.TempDir(), "audio.wav")
+		assert.NoError(t, os.WriteFile(path, audio[:100], 0o644))
+
+		n, err := c.DownloadTTSJobAudioFile(context.Background(), job, path)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(audio)-100), n)
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, audio, data)
+	})
+	t.Run("size mismatch", func(t *testing.T) {
+		t.Parallel()
+		srv := newServer(false)
+		defer srv.Close()
+
+		c := NewClient()
+		defer c.Close()
+
+		job := &TTSJob{ID: "job"}
+		job.Output.URL = srv.URL + "/audio.wav"
+		job.Output.Size = len(audio) + 1
+
+		_, err := c.DownloadTTSJobAudio(context.Background(), job, &bytes.Buffer{})
+		assert.ErrorIs(t, err, ErrSizeMismatch)
+	})
+	t.Run("range mismatch", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			// NOTE: the server sends a different range than requested
+			w.Header().Set("Content-Range", "bytes 0-99/"+strconv.Itoa(len(audio)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(audio[:100])
+		}))
+		defer srv.Close()
+
+		c := NewClient()
+		defer c.Close()
+
+		job := &TTSJob{ID: "job"}
+		job.Output.URL = srv.URL + "/audio.wav"
+		job.Output.Size = len(audio)
+
+		var buf bytes.Buffer
+		n, err := c.DownloadTTSJobAudio(context.Background(), job, &buf, WithDownloadOffset(100))
+		assert.ErrorIs(t, err, ErrSizeMismatch)
+		assert.Zero(t, n)
+		assert.Empty(t, buf.Bytes())
+	})
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			name      string
+			status    int
+			retryable bool
+			reqs      int32
+		}{
+			{name: "not found", status: http.StatusNotFound, reqs: 1},
+			{name: "unavailable", status: http.StatusServiceUnavailable, retryable: true, reqs: 3},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				var reqs atomic.Int32
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					reqs.Add(1)
+					w.Header().Set("Content-Type", "application/xml")
+					w.WriteHeader(tc.status)
+					fmt.Fprint(w, "<Error>"+strings.Repeat("x", 2*request.MaxErrorSnippetSize)+"</Error>")
+				}))
+				defer srv.Close()
+
+				c := NewClient()
+				defer c.Close()
+
+				job := &TTSJob{ID: "job"}
+				job.Output.URL = srv.URL + "/audio.wav"
+
+				_, err := c.DownloadTTSJobAudio(context.Background(), job, &bytes.Buffer{}, WithDownloadRetries(2, time.Millisecond))
+				var apiErr *APIError
+				if assert.ErrorAs(t, err, &apiErr) {
+					assert.Equal(t, tc.status, apiErr.StatusCode)
+				}
+				assert.Equal(t, tc.status == http.StatusNotFound, errors.Is(err, ErrNotFound))
+				assert.Equal(t, tc.retryable, IsRetryable(err))
+				assert.Contains(t, err.Error(), "<Error>")
+				assert.Less(t, len(err.Error()), 2*request.MaxErrorSnippetSize)
+				assert.Equal(t, tc.reqs, reqs.Load())
+			})
+		}
+	})
+	t.Run("no output", func(t *testing.T) {
+		t.Parallel()
+		c := NewClient()
+		defer c.Close()
+
+		_, err := c.DownloadTTSJobAudio(context.Background(), &TTSJob{ID: "job"}, &bytes.Buffer{})
+		assert.ErrorIs(t, err, ErrNoJobOutput)
+	})
+}
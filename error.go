@@ -4,23 +4,84 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/milosgajdos/go-playht/request"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var (
 	// ErrUnknown is returned when an unknown error occurrs.
 	ErrUnknown = errors.New("unknown error")
+	// ErrUnauthorized is matched by API errors with 401 status code.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is matched by API errors with 403 status code.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is matched by API errors with 404 status code.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is matched by API errors returned when the API rate limit is exceeded.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrInsufficientCredits is matched by API errors returned when the account has run out of credits.
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	// ErrNoGrpcConn is returned when the gRPC API is used by the client without gRPC connection.
+	ErrNoGrpcConn = errors.New("no gRPC client connection")
+	// ErrLeaseExpired is matched by gRPC errors returned when the stream lease has expired or is invalid.
+	// NOTE: matching is a best-effort heuristic: the API has no dedicated error code for it,
+	// so only Unauthenticated errors whose message mentions an expired or invalid lease match.
+	ErrLeaseExpired = errors.New("lease expired")
 )
 
+// RequestIDHeaders are the response headers which may carry the API request ID.
+var RequestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Amzn-Requestid",
+	"X-Correlation-Id",
+}
+
+// APIErrorHeaders are the response headers retained in APIError.
+var APIErrorHeaders = append([]string{
+	"Retry-After",
+	"X-Ratelimit-Limit",
+	"X-Ratelimit-Remaining",
+	"X-Ratelimit-Reset",
+}, RequestIDHeaders...)
+
 // APIError is a pseudo-sum type API error type.
 type APIError struct {
 	Generic        *ErrGeneric
 	Internal       *ErrInternal
 	RateLimit      *ErrRateLimit
 	UnexpecedError json.RawMessage
+	// ContentType is the response content type.
+	ContentType string
+	// StatusCode is the HTTP response status code.
+	StatusCode int
+	// Header contains the response headers listed in APIErrorHeaders.
+	Header http.Header
+	// RequestID is the API request ID, if any.
+	RequestID string
+	// Method is the HTTP request method.
+	Method string
+	// URL is the HTTP request URL.
+	URL string
 }
 
 func (e *APIError) Error() string {
+	msg := e.message()
+	if e.StatusCode == 0 {
+		return msg
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("%d %s (request ID %s): %s", e.StatusCode, http.StatusText(e.StatusCode), e.RequestID, msg)
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), msg)
+}
+
+func (e *APIError) message() string {
 	if e.Generic != nil {
 		return e.Generic.Error()
 	}
@@ -31,11 +92,66 @@ func (e *APIError) Error() string {
 		return e.RateLimit.Error()
 	}
 	if len(e.UnexpecedError) > 0 {
-		return string(e.UnexpecedError)
+		return request.Snippet(e.UnexpecedError, request.MaxErrorSnippetSize)
 	}
 	return ErrUnknown.Error()
 }
 
+// SetRawBody implements request.RawBodyError.
+// It's used when the response body is not JSON.
+func (e *APIError) SetRawBody(contentType string, body []byte) {
+	e.ContentType = contentType
+	if strings.Contains(string(body), "Rate limit exceeded") {
+		e.RateLimit = &ErrRateLimit{Message: string(body)}
+		return
+	}
+	if len(body) > 0 {
+		e.UnexpecedError = body
+	}
+}
+
+// SetResponse implements request.ResponseError.
+func (e *APIError) SetResponse(resp *http.Response) {
+	e.StatusCode = resp.StatusCode
+	e.ContentType = resp.Header.Get("Content-Type")
+	e.Header = make(http.Header)
+	for _, key := range APIErrorHeaders {
+		if vals := resp.Header.Values(key); len(vals) > 0 {
+			e.Header[http.CanonicalHeaderKey(key)] = vals
+		}
+	}
+	for _, key := range RequestIDHeaders {
+		if id := resp.Header.Get(key); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.URL = resp.Request.URL.String()
+		}
+	}
+}
+
+// Is allows matching APIError against the sentinel errors via errors.Is.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.RateLimit != nil
+	case ErrInsufficientCredits:
+		return e.StatusCode == http.StatusPaymentRequired ||
+			strings.Contains(strings.ToLower(e.message()), "insufficient credits")
+	}
+	return false
+}
+
 func (e *APIError) UnmarshalJSON(data []byte) error {
 	if strings.Contains(string(data), "Rate limit exceeded") {
 		e.RateLimit = &ErrRateLimit{Message: string(data)}
@@ -58,7 +174,7 @@ func (e *APIError) UnmarshalJSON(data []byte) error {
 
 	e.UnexpecedError = data
 
-	return fmt.Errorf("failed decoding API error: %s", string(data))
+	return nil
 }
 
 // ErrGeneric is a generic API error.
@@ -101,3 +217,131 @@ type ErrRateLimit struct {
 func (e ErrRateLimit) Error() string {
 	return e.Message
 }
+
+// StreamStatusError is returned when the gRPC
+// stream reports an error or cancelation status.
+type StreamStatusError struct {
+	// Code is the stream status code.
+	Code pb.Code
+	// Messages are the stream status messages.
+	Messages []string
+	// Sequence is the last stream sequence received.
+	Sequence int32
+}
+
+// Error implements error interface.
+func (e *StreamStatusError) Error() string {
+	msg := fmt.Sprintf("stream status %s at sequence %d", e.Code, e.Sequence)
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	return msg
+}
+
+// SequenceError is returned when the gRPC stream
+// chunks arrive out of order or some of them are missing.
+type SequenceError struct {
+	// Expected is the expected chunk sequence.
+	Expected int32
+	// Got is the received chunk sequence.
+	Got int32
+}
+
+// Error implements error interface.
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("unexpected stream sequence: expected %d, got %d", e.Expected, e.Got)
+}
+
+// JobFailedError is returned when the TTS job has failed.
+type JobFailedError struct {
+	// ID is the job ID.
+	ID string
+	// Status is the job status.
+	Status TTSJobStatus
+	// Message is the job error message, if any.
+	Message string
+}
+
+// Error implements error interface.
+func (e *JobFailedError) Error() string {
+	msg := fmt.Sprintf("job %s failed", e.ID)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// GrpcError is returned when the gRPC stream fails.
+// It can be matched against ErrRateLimited, ErrUnauthorized,
+// ErrForbidden and ErrLeaseExpired sentinel errors via errors.Is.
+type GrpcError struct {
+	// Code is the gRPC status code.
+	Code codes.Code
+	// Message is the gRPC status message.
+	Message string
+	// Details are the gRPC status details.
+	Details []any
+	// Trailer is the stream trailing metadata.
+	Trailer metadata.MD
+	status  *status.Status
+}
+
+// NewGrpcError returns *GrpcError created from the gRPC status error err and trailer metadata.
+// If err is not a gRPC status error, it's returned unchanged.
+func NewGrpcError(err error, trailer metadata.MD) error {
+	var grpcErr *GrpcError
+	if errors.As(err, &grpcErr) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return &GrpcError{
+		Code:    st.Code(),
+		Message: st.Message(),
+		Details: st.Details(),
+		Trailer: trailer,
+		status:  st,
+	}
+}
+
+// Error implements error interface.
+func (e *GrpcError) Error() string {
+	return fmt.Sprintf("grpc error: code = %s desc = %s", e.Code, e.Message)
+}
+
+// GRPCStatus returns the gRPC status.
+// It allows status.FromError to handle GrpcError.
+func (e *GrpcError) GRPCStatus() *status.Status {
+	if e.status != nil {
+		return e.status
+	}
+	return status.New(e.Code, e.Message)
+}
+
+// Is allows matching GrpcError against the sentinel errors via errors.Is.
+func (e *GrpcError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.Code == codes.ResourceExhausted
+	case ErrUnauthorized:
+		return e.Code == codes.Unauthenticated
+	case ErrForbidden:
+		return e.Code == codes.PermissionDenied
+	case ErrLeaseExpired:
+		return e.leaseExpired()
+	}
+	return false
+}
+
+// leaseExpired returns true if the error was caused by expired or invalid lease.
+// NOTE: the API doesn't use dedicated error code for this so we inspect the message.
+func (e *GrpcError) leaseExpired() bool {
+	if e.Code != codes.Unauthenticated {
+		return false
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "lease") &&
+		(strings.Contains(msg, "expire") || strings.Contains(msg, "invalid"))
+}
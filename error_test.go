@@ -0,0 +1,95 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"error_message":"invalid credentials","error_id":"UNAUTHORIZED"}`, ErrUnauthorized},
+		{"forbidden", http.StatusForbidden, `{"error_message":"forbidden","error_id":"FORBIDDEN"}`, ErrForbidden},
+		{"not found", http.StatusNotFound, `{"error_message":"not found","error_id":"NOT_FOUND"}`, ErrNotFound},
+		{"rate limited", http.StatusTooManyRequests, `{"message":"Rate limit exceeded"}`, ErrRateLimited},
+		{"insufficient credits", http.StatusForbidden, `{"error_message":"Insufficient credits","error_id":"FORBIDDEN"}`, ErrInsufficientCredits},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Request-Id", "req-id")
+				w.Header().Set("Retry-After", "1")
+				w.Header().Set("X-Other", "other")
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			c := NewClient(WithBaseURL(srv.URL))
+			defer c.Close()
+
+			_, err := c.GetVoices(context.Background())
+			assert.ErrorIs(t, err, tc.sentinel)
+
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tc.status, apiErr.StatusCode)
+			assert.Equal(t, "req-id", apiErr.RequestID)
+			assert.Equal(t, "1", apiErr.Header.Get("Retry-After"))
+			assert.Empty(t, apiErr.Header.Get("X-Other"))
+			assert.Equal(t, http.MethodGet, apiErr.Method)
+			assert.Equal(t, srv.URL+"/v2/voices", apiErr.URL)
+		})
+	}
+}
+
+func TestAPIErrorRawBody(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		ct     string
+		status int
+		body   string
+		msg    string
+	}{
+		{"html", "text/html", http.StatusBadGateway, "<html>Bad Gateway</html>", "502 Bad Gateway: <html>Bad Gateway</html>"},
+		{"empty", "", http.StatusServiceUnavailable, "", "503 Service Unavailable: unknown error"},
+		{"unexpected json", "application/json", http.StatusBadRequest, `{"foo":"bar"}`, `400 Bad Request: {"foo":"bar"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header()["Content-Type"] = []string{tc.ct}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			c := NewClient(WithBaseURL(srv.URL))
+			defer c.Close()
+
+			_, err := c.GetVoices(context.Background())
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tc.status, apiErr.StatusCode)
+			assert.Equal(t, tc.msg, apiErr.Error())
+		})
+	}
+}
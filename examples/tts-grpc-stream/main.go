@@ -47,14 +47,9 @@ func main() {
 	// Creates an API client with default options.
 	// * it reads PLAYHT_SECRET_KEY and PLAYHT_USER_ID env vars
 	// * uses playht.BaserURL and APIv2 to create API endpoint URL
+	// * the gRPC stream lease is obtained and refreshed automatically
 	client := playht.NewClient(playht.WithGRPCClient(conn))
-
-	lease, err := client.CreateLease(ctx, &playht.CreateLeaseReq{})
-	if err != nil {
-		log.Fatalf("failed getting a lease: %v", err)
-	}
-
-	log.Printf("successfully got PlayHT lease; expires: %s", lease.Expires())
+	defer client.Close()
 
 	voices, err := client.GetVoices(context.Background())
 	if err != nil {
@@ -70,7 +65,6 @@ func main() {
 	log.Printf("using voice: %s", voice)
 
 	req := &pb.TtsRequest{
-		Lease: lease.Data,
 		Params: &pb.TtsParams{
 			Text:       []string{input},
 			Voice:      voice,
@@ -52,10 +52,10 @@ func main() {
 
 	log.Printf("successfully created a new TTS job: %#v", job)
 
-	jobInfo, err := client.GetTTSJob(context.Background(), job.ID)
+	jobInfo, err := client.WaitForTTSJob(context.Background(), job.ID)
 	if err != nil {
-		log.Fatalf("failed getting %v job info: %v", job.ID, err)
+		log.Fatalf("failed waiting for %v job: %v", job.ID, err)
 	}
 
-	log.Printf("successfully got job info: %#v", jobInfo)
+	log.Printf("successfully completed job: %#v", jobInfo)
 }
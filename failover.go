@@ -0,0 +1,131 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultTransportOrder is the default failover transport preference order.
+var DefaultTransportOrder = []Transport{TransportGRPC, TransportHTTP}
+
+// FailoverOptions configure the failover synthesizer.
+type FailoverOptions struct {
+	// Order is the transport preference order.
+	Order []Transport
+	// GrpcStreamOptions are applied to every gRPC stream.
+	GrpcStreamOptions []GrpcStreamOption
+	// ShouldFailover reports whether the failed synthesis should
+	// fail over to the next transport. By default it fails over
+	// on every error unless the request context is done.
+	ShouldFailover func(error) bool
+	// OnFailover is called when the synthesis fails over
+	// from the transport which failed with err.
+	OnFailover func(from Transport, err error)
+}
+
+// FailoverOption is a failover synthesizer functional option.
+type FailoverOption func(*FailoverOptions)
+
+// WithTransportOrder sets the transport preference order.
+func WithTransportOrder(order ...Transport) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.Order = order
+	}
+}
+
+// WithFailoverGrpcOptions sets the options applied to every gRPC stream.
+func WithFailoverGrpcOptions(opts ...GrpcStreamOption) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.GrpcStreamOptions = opts
+	}
+}
+
+// WithShouldFailover sets the func which reports whether the failed synthesis should fail over.
+func WithShouldFailover(fn func(error) bool) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.ShouldFailover = fn
+	}
+}
+
+// WithOnFailover sets the func called when the synthesis fails over.
+func WithOnFailover(fn func(from Transport, err error)) FailoverOption {
+	return func(o *FailoverOptions) {
+		o.OnFailover = fn
+	}
+}
+
+// FailoverSynthesizer synthesizes speech via the first transport in the preference order
+// and fails over to the next transport if the synthesis fails before any audio bytes
+// have been written. The transport which served the request is reported in SynthesisResult.
+type FailoverSynthesizer struct {
+	synths map[Transport]Synthesizer
+	opts   FailoverOptions
+}
+
+// NewFailoverSynthesizer creates a new failover synthesizer and returns it.
+// By default it prefers gRPC stream and fails over to HTTP stream.
+func NewFailoverSynthesizer(c *Client, opts ...FailoverOption) *FailoverSynthesizer {
+	options := FailoverOptions{
+		Order: DefaultTransportOrder,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	return &FailoverSynthesizer{
+		synths: map[Transport]Synthesizer{
+			TransportGRPC: NewGrpcSynthesizer(c, options.GrpcStreamOptions...),
+			TransportHTTP: NewHTTPSynthesizer(c),
+			TransportJob:  NewJobSynthesizer(c),
+		},
+		opts: options,
+	}
+}
+
+// Synthesize implements Synthesizer.
+// If all transports fail, the returned error joins the errors of all transports.
+func (s *FailoverSynthesizer) Synthesize(ctx context.Context, req *SynthesisRequest, w io.Writer) (*SynthesisResult, error) {
+	if len(s.opts.Order) == 0 {
+		return nil, errors.New("no transport configured")
+	}
+
+	var (
+		res  *SynthesisResult
+		errs []error
+	)
+	for i, t := range s.opts.Order {
+		synth, ok := s.synths[t]
+		if !ok {
+			return nil, fmt.Errorf("unsupported transport: %s", t)
+		}
+
+		var err error
+		res, err = synth.Synthesize(ctx, req, w)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t, err))
+
+		// NOTE: we can't fail over once the audio has been written
+		if res.Bytes > 0 || !s.shouldFailover(ctx, err) || i == len(s.opts.Order)-1 {
+			break
+		}
+		if s.opts.OnFailover != nil {
+			s.opts.OnFailover(t, err)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+func (s *FailoverSynthesizer) shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if s.opts.ShouldFailover != nil {
+		return s.opts.ShouldFailover(err)
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package playht
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSynthesizer writes data and then returns err.
+type fakeSynthesizer struct {
+	transport Transport
+	data      string
+	err       error
+	calls     int
+}
+
+func (s *fakeSynthesizer) Synthesize(_ context.Context, _ *SynthesisRequest, w io.Writer) (*SynthesisResult, error) {
+	s.calls++
+	return synthesize(s.transport, &SynthesisRequest{}, w, func(w io.Writer) (_ time.Duration, err error) {
+		_, err = io.WriteString(w, s.data)
+		if err != nil {
+			return 0, err
+		}
+		return 0, s.err
+	})
+}
+
+func TestFailoverSynthesizer(t *testing.T) {
+	t.Parallel()
+	t.Run("grpc lease failure", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/leases":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error_message":"unauthorized"}`)
+			case "/v2/tts/stream":
+				fmt.Fprint(w, "audio")
+			}
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		var failed []Transport
+		s := NewFailoverSynthesizer(c, WithOnFailover(func(from Transport, err error) {
+			assert.Error(t, err)
+			failed = append(failed, from)
+		}))
+		buf := &bytes.Buffer{}
+		res, err := s.Synthesize(context.Background(), &SynthesisRequest{Text: "hello"}, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, TransportHTTP, res.Transport)
+		assert.Equal(t, []Transport{TransportGRPC}, failed)
+		assert.Equal(t, "audio", buf.String())
+	})
+	t.Run("no grpc conn", func(t *testing.T) {
+		t.Parallel()
+		lease := makeTestLease(uint32(time.Now().Unix()-HTEpoch), 3600, `{}`)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/leases":
+				_, _ = w.Write(lease)
+			case "/v2/tts/stream":
+				fmt.Fprint(w, "audio")
+			}
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		var failErr error
+		s := NewFailoverSynthesizer(c, WithOnFailover(func(_ Transport, err error) {
+			failErr = err
+		}))
+		buf := &bytes.Buffer{}
+		res, err := s.Synthesize(context.Background(), &SynthesisRequest{Text: "hello"}, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, TransportHTTP, res.Transport)
+		assert.ErrorIs(t, failErr, ErrNoGrpcConn)
+		assert.Equal(t, "audio", buf.String())
+	})
+	t.Run("order", func(t *testing.T) {
+		t.Parallel()
+		grpcSynth := &fakeSynthesizer{transport: TransportGRPC, data: "grpc"}
+		httpSynth := &fakeSynthesizer{transport: TransportHTTP, err: errors.New("http error")}
+		s := NewFailoverSynthesizer(nil, WithTransportOrder(TransportHTTP, TransportGRPC))
+		s.synths = map[Transport]Synthesizer{TransportGRPC: grpcSynth, TransportHTTP: httpSynth}
+
+		buf := &bytes.Buffer{}
+		res, err := s.Synthesize(context.Background(), &SynthesisRequest{}, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, TransportGRPC, res.Transport)
+		assert.Equal(t, "grpc", buf.String())
+		assert.Equal(t, 1, httpSynth.calls)
+	})
+	t.Run("no failover after write", func(t *testing.T) {
+		t.Parallel()
+		streamErr := errors.New("stream error")
+		grpcSynth := &fakeSynthesizer{transport: TransportGRPC, data: "partial", err: streamErr}
+		httpSynth := &fakeSynthesizer{transport: TransportHTTP, data: "http"}
+		s := NewFailoverSynthesizer(nil)
+		s.synths = map[Transport]Synthesizer{TransportGRPC: grpcSynth, TransportHTTP: httpSynth}
+
+		res, err := s.Synthesize(context.Background(), &SynthesisRequest{}, io.Discard)
+		assert.ErrorIs(t, err, streamErr)
+		assert.Equal(t, TransportGRPC, res.Transport)
+		assert.Equal(t, int64(7), res.Bytes)
+		assert.Equal(t, 0, httpSynth.calls)
+	})
+	t.Run("all failed", func(t *testing.T) {
+		t.Parallel()
+		grpcErr, httpErr := errors.New("grpc error"), errors.New("http error")
+		s := NewFailoverSynthesizer(nil)
+		s.synths = map[Transport]Synthesizer{
+			TransportGRPC: &fakeSynthesizer{transport: TransportGRPC, err: grpcErr},
+			TransportHTTP: &fakeSynthesizer{transport: TransportHTTP, err: httpErr},
+		}
+
+		_, err := s.Synthesize(context.Background(), &SynthesisRequest{}, io.Discard)
+		assert.ErrorIs(t, err, grpcErr)
+		assert.ErrorIs(t, err, httpErr)
+	})
+}
@@ -7,26 +7,16 @@ import (
 )
 
 // MakeGrpcStreamRequest creates a new gRPC stream request from lease and req.
-// NOTE: gRPC doesn't provide VoiceEngine and Emotion configuration at the moment.
+// If lease is nil, Client.TTSGrpcStream obtains the lease from the client LeaseManager.
+// Zero value optional parameters are not set. VoiceEngine and Emotion are sent
+// in the proto Other JSON string as gRPC doesn't provide fields for them.
 func MakeGrpcStreamRequest(lease []byte, req *CreateTTSStreamReq) *pb.TtsRequest {
-	ttsReq := &pb.TtsRequest{
-		Lease: slices.Clone(lease),
-		Params: &pb.TtsParams{
-			Text:          []string{req.Text},
-			Voice:         req.Voice,
-			Quality:       ToPbQuality(req.Quality),
-			Format:        ToPbFormat(req.OutputFormat),
-			SampleRate:    Int32Ptr(req.SampleRate),
-			Speed:         Float32Ptr(req.Speed),
-			Seed:          Int32Ptr(req.Seed),
-			Temperature:   Float32Ptr(req.Temperature),
-			StyleGuidance: Float32Ptr(req.StyleGuidance),
-			VoiceGuidance: Float32Ptr(req.VoiceGuidance),
-			TextGuidance:  Float32Ptr(req.TextGuidance),
-		},
+	// NOTE: this can't fail as the stream request has no Other params
+	params, _ := ToPbParams(StreamReqParams(req))
+	return &pb.TtsRequest{
+		Lease:  slices.Clone(lease),
+		Params: params,
 	}
-
-	return ttsReq
 }
 
 // ToPbQuality converts Quality to its proto representation.
@@ -42,6 +32,8 @@ func ToPbQuality(q Quality) *pb.Quality {
 		return qualityPtr(pb.Quality_QUALITY_HIGH)
 	case Premium:
 		return qualityPtr(pb.Quality_QUALITY_PREMIUM)
+	case Unspecified:
+		return qualityPtr(pb.Quality_QUALITY_UNSPECIFIED)
 	default:
 		return nil
 	}
@@ -60,6 +52,8 @@ func ToPbFormat(f OutputFormat) *pb.Format {
 		return formatPtr(pb.Format_FORMAT_FLAC)
 	case Mulaw:
 		return formatPtr(pb.Format_FORMAT_MULAW)
+	case Raw:
+		return formatPtr(pb.Format_FORMAT_RAW)
 	default:
 		return nil
 	}
@@ -82,3 +76,8 @@ func Int32Ptr(i int32) *int32 {
 func Float32Ptr(f float32) *float32 {
 	return &f
 }
+
+// StringPtr returns pointer to s.
+func StringPtr(s string) *string {
+	return &s
+}
@@ -20,9 +20,9 @@ type CreateTTSJobReq struct {
 	OutputFormat  OutputFormat `json:"output_format"`
 	VoiceEngine   VoiceEngine  `json:"voice_engine,omitempty"`
 	Emotion       Emotion      `json:"emotion,omitempty"`
-	Speed         float32      `json:"speed"`
+	Speed         float32      `json:"speed,omitempty"`
 	Temperature   float32      `json:"temperature,omitempty"`
-	SampleRate    int32        `json:"sample_rate"`
+	SampleRate    int32        `json:"sample_rate,omitempty"`
 	Seed          uint8        `json:"seed,omitempty"`
 	VoiceGuidance float32      `json:"voice_guidance,omitempty"`
 	StyleGuidance float32      `json:"style_guidance,omitempty"`
@@ -46,8 +46,27 @@ type TTSJob struct {
 		URL      string  `json:"url"`
 		Duration float64 `json:"duration"`
 	} `json:"output"`
-	Status string `json:"status,omitempty"`
-	Links  []Link `json:"_links,omitempty"`
+	Status TTSJobStatus `json:"status,omitempty"`
+	Links  []Link       `json:"_links,omitempty"`
+}
+
+// TTSJobStatus is the TTS job status.
+type TTSJobStatus string
+
+const (
+	TTSJobPending    TTSJobStatus = "pending"
+	TTSJobGenerating TTSJobStatus = "generating"
+	TTSJobComplete   TTSJobStatus = "complete"
+	TTSJobFailed     TTSJobStatus = "failed"
+)
+
+func (s TTSJobStatus) String() string {
+	return string(s)
+}
+
+// IsTerminal returns true if the job is no longer being processed.
+func (s TTSJobStatus) IsTerminal() bool {
+	return s == TTSJobComplete || s == TTSJobFailed
 }
 
 // CreateTTSJob creates a new Text-to-Speech (TTS) job that converts input text into audio asynchronously
@@ -122,6 +141,7 @@ func (c *Client) GetTTSJob(ctx context.Context, id string) (*TTSJob, error) {
 
 // GetTTSJobAudioStream retrieves the TTS job audio stream from the job with the given id.
 // It streams audio in the MP3 format or returns error if the file was not generated as MP3.
+// Use DownloadTTSJobAudio to download the audio of the completed job in any format.
 func (c *Client) GetTTSJobAudioStream(ctx context.Context, w io.Writer, id string) error {
 	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts/" + id)
 	if err != nil {
@@ -153,16 +173,48 @@ func (c *Client) GetTTSJobAudioStream(ctx context.Context, w io.Writer, id strin
 // CreateTTSJobWithProgressStream creates a new Text-to-Speech (TTS) SSE stream that converts input text into audio
 // asynchronously and returns the job progress SSE stream URL. If w is not nil, the events are streamed into it.
 func (c *Client) CreateTTSJobWithProgressStream(ctx context.Context, w io.Writer, createReq *CreateTTSJobReq) (string, error) {
-	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts")
+	resp, err := c.createTTSJobProgress(ctx, createReq)
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
+
+	streamURL := resp.Header.Get("Content-Location")
+	if w != nil {
+		if _, err := io.Copy(w, resp.Body); err != nil {
+			return streamURL, err
+		}
+		return streamURL, nil
+	}
+	return streamURL, nil
+}
+
+// GetTTSJobProgressStream retrieves the TTS job progress SSE stream for the job with the given id and streams it into w.
+func (c *Client) GetTTSJobProgressStream(ctx context.Context, w io.Writer, id string) error {
+	resp, err := c.getTTSJobProgress(ctx, id, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// createTTSJobProgress creates a new TTS job and returns the job progress SSE stream response.
+func (c *Client) createTTSJobProgress(ctx context.Context, createReq *CreateTTSJobReq) (*http.Response, error) {
+	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts")
+	if err != nil {
+		return nil, err
+	}
 
 	var body = &bytes.Buffer{}
 	enc := json.NewEncoder(body)
 	enc.SetEscapeHTML(false)
 	if err := enc.Encode(createReq); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	options := []request.HTTPOption{
@@ -174,30 +226,18 @@ func (c *Client) CreateTTSJobWithProgressStream(ctx context.Context, w io.Writer
 
 	req, err := request.NewHTTP(ctx, http.MethodPost, u.String(), body, options...)
 	if err != nil {
-		return "", err
-	}
-
-	resp, err := request.Do[*APIError](c.opts.HTTPClient, req)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	streamURL := resp.Header.Get("Content-Location")
-	if w != nil {
-		if _, err := io.Copy(w, resp.Body); err != nil {
-			return streamURL, err
-		}
-		return streamURL, nil
-	}
-	return streamURL, nil
+	return request.Do[*APIError](c.opts.HTTPClient, req)
 }
 
-// GetTTSJobProgressStream retrieves the TTS job progress SSE stream for the job with the given id and streams it into w.
-func (c *Client) GetTTSJobProgressStream(ctx context.Context, w io.Writer, id string) error {
+// getTTSJobProgress returns the progress SSE stream response of the job with the given id.
+// If lastEventID is not empty, it is sent in the Last-Event-ID header to resume the stream.
+func (c *Client) getTTSJobProgress(ctx context.Context, id, lastEventID string) (*http.Response, error) {
 	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts/" + id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	options := []request.HTTPOption{
@@ -205,20 +245,14 @@ func (c *Client) GetTTSJobProgressStream(ctx context.Context, w io.Writer, id st
 		request.WithSetHeader(UserIDHeader, c.opts.UserID),
 		request.WithAddHeader("Accept", "text/event-stream"),
 	}
-
-	req, err := request.NewHTTP(ctx, http.MethodGet, u.String(), nil, options...)
-	if err != nil {
-		return err
+	if lastEventID != "" {
+		options = append(options, request.WithSetHeader("Last-Event-ID", lastEventID))
 	}
 
-	resp, err := request.Do[*APIError](c.opts.HTTPClient, req)
+	req, err := request.NewHTTP(ctx, http.MethodGet, u.String(), nil, options...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		return err
-	}
-	return nil
+	return request.Do[*APIError](c.opts.HTTPClient, req)
 }
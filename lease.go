@@ -5,12 +5,15 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/milosgajdos/go-playht/client"
 	"github.com/milosgajdos/go-playht/request"
 )
 
@@ -18,14 +21,157 @@ const (
 	// HTEpoch is the HT Lease epoch.
 	// I've no idea why but whatever.
 	HTEpoch int64 = 1519257480 // 2018-02-21 23:58:00 UTC
+	// LeaseSignatureSize is the size of the lease signature in bytes.
+	LeaseSignatureSize = 64
+	// LeaseHeaderSize is the size of the lease header in bytes.
+	// The header consists of the signature followed by
+	// big endian encoded created and duration fields.
+	LeaseHeaderSize = LeaseSignatureSize + 8
+)
+
+var (
+	// ErrInvalidLease is returned when the lease data is malformed.
+	ErrInvalidLease = errors.New("invalid lease")
 )
 
 // Lease for gRPC stream.
+// The binary lease layout is as follows:
+//   - [0:64] signature
+//   - [64:68] created: big endian int32 seconds since HTEpoch
+//   - [68:72] duration: big endian int32 seconds
+//   - [72:] JSON encoded metadata
 type Lease struct {
-	Data     []byte
-	Created  time.Time
-	Duration time.Duration
-	Metadata map[string]any
+	Data      []byte
+	Signature []byte
+	Created   time.Time
+	Duration  time.Duration
+	Metadata  LeaseMetadata
+}
+
+// LeaseMetadata is the lease metadata.
+// Metadata fields which are not known to this module
+// are available in Raw for forward compatibility.
+type LeaseMetadata struct {
+	// UserID is the ID of the user the lease was issued to.
+	UserID string `json:"user_id,omitempty"`
+	// Plan is the user subscription plan.
+	Plan string `json:"plan,omitempty"`
+	// InferenceAddress is the gRPC streaming endpoint address.
+	InferenceAddress string `json:"inference_address,omitempty"`
+	// PremiumInferenceAddress is the gRPC streaming endpoint address
+	// used for the premium quality streaming.
+	PremiumInferenceAddress string `json:"premium_inference_address,omitempty"`
+	// AllowedEndpoints lists the endpoints the lease is valid for.
+	AllowedEndpoints []string `json:"allowed_endpoints,omitempty"`
+	// Limits are the usage limits of the lease.
+	Limits *LeaseLimits `json:"limits,omitempty"`
+	// Raw contains all the decoded metadata fields.
+	Raw map[string]any `json:"-"`
+}
+
+// LeaseLimits are the lease usage limits.
+type LeaseLimits struct {
+	// Concurrency is the maximum number of concurrent streams.
+	Concurrency int `json:"concurrency,omitempty"`
+	// RequestsPerMinute is the maximum number of streams per minute.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	// MaxTextLength is the maximum text length per stream.
+	MaxTextLength int `json:"max_text_length,omitempty"`
+}
+
+// leaseMetadata prevents infinite JSON (un)marshaling recursion.
+type leaseMetadata LeaseMetadata
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The known fields are decoded on the best effort basis:
+// the fields whose values can't be decoded are left empty
+// and only remain available in Raw.
+func (m *LeaseMetadata) UnmarshalJSON(data []byte) error {
+	raw := map[string]any{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var md leaseMetadata
+	for key, val := range raw {
+		b, err := json.Marshal(map[string]any{key: val})
+		if err != nil {
+			continue
+		}
+		// NOTE: we decode the fields one by one so
+		// a single malformed field doesn't void the rest.
+		_ = json.Unmarshal(b, &md)
+	}
+	md.Raw = raw
+
+	*m = LeaseMetadata(md)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The known fields take precedence over the fields in Raw.
+func (m LeaseMetadata) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(leaseMetadata(m))
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]any{}
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+
+	md := make(map[string]any, len(m.Raw)+len(known))
+	for key, val := range m.Raw {
+		md[key] = val
+	}
+	for key, val := range known {
+		md[key] = val
+	}
+
+	return json.Marshal(md)
+}
+
+// GrpcAddr returns the gRPC streaming endpoint address.
+// If premium is true and the premium address is available, it is returned instead.
+// It returns the default GrpcAddr if the metadata doesn't contain any address.
+func (m LeaseMetadata) GrpcAddr(premium bool) string {
+	if premium && m.PremiumInferenceAddress != "" {
+		return m.PremiumInferenceAddress
+	}
+	if m.InferenceAddress != "" {
+		return m.InferenceAddress
+	}
+	return GrpcAddr
+}
+
+// ParseLease parses the binary lease data and returns the Lease.
+// It returns error wrapping ErrInvalidLease if data is malformed.
+func ParseLease(data []byte) (*Lease, error) {
+	if len(data) < LeaseHeaderSize {
+		return nil, fmt.Errorf("%w: expected at least %d bytes, got %d", ErrInvalidLease, LeaseHeaderSize, len(data))
+	}
+
+	created := int32(binary.BigEndian.Uint32(data[LeaseSignatureSize : LeaseSignatureSize+4]))
+	duration := int32(binary.BigEndian.Uint32(data[LeaseSignatureSize+4 : LeaseHeaderSize]))
+	if duration < 0 {
+		return nil, fmt.Errorf("%w: negative duration: %d", ErrInvalidLease, duration)
+	}
+
+	var md LeaseMetadata
+	if err := json.Unmarshal(data[LeaseHeaderSize:], &md); err != nil {
+		return nil, fmt.Errorf("%w: failed reading metadata: %v", ErrInvalidLease, err)
+	}
+
+	data = bytes.Clone(data)
+
+	return &Lease{
+		Data:      data,
+		Signature: data[:LeaseSignatureSize],
+		Created:   time.Unix(HTEpoch, 0).Add(time.Duration(created) * time.Second),
+		Duration:  time.Duration(duration) * time.Second,
+		Metadata:  md,
+	}, nil
 }
 
 // Expires returns a timestamp when the Lease expires
@@ -33,6 +179,70 @@ func (l *Lease) Expires() time.Time {
 	return l.Created.Add(l.Duration)
 }
 
+// Valid returns true if the lease contains data and has not expired at the given time.
+// NOTE: the lease creation time is not checked as it's set by the
+// API server whose clock might be slightly ahead of the local one.
+func (l *Lease) Valid(now time.Time) bool {
+	if len(l.Data) == 0 && len(l.Signature) == 0 {
+		return false
+	}
+	return now.Before(l.Expires())
+}
+
+// RemainingTTL returns the time remaining until the lease expires.
+// It returns zero if the lease has already expired.
+func (l *Lease) RemainingTTL() time.Duration {
+	if ttl := time.Until(l.Expires()); ttl > 0 {
+		return ttl
+	}
+	return 0
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// If the lease was parsed from binary data, the original data is returned
+// as the signature is only valid for the exact bytes it was issued with.
+func (l *Lease) MarshalBinary() ([]byte, error) {
+	if len(l.Data) > 0 {
+		return bytes.Clone(l.Data), nil
+	}
+
+	if len(l.Signature) != LeaseSignatureSize {
+		return nil, fmt.Errorf("%w: expected %d bytes signature, got %d", ErrInvalidLease, LeaseSignatureSize, len(l.Signature))
+	}
+
+	md, err := json.Marshal(l.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed encoding metadata: %v", ErrInvalidLease, err)
+	}
+
+	created := l.Created.Unix() - HTEpoch
+	if created < 0 || created > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: created out of range: %s", ErrInvalidLease, l.Created)
+	}
+	duration := int64(l.Duration / time.Second)
+	if duration < 0 || duration > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: duration out of range: %s", ErrInvalidLease, l.Duration)
+	}
+
+	data := make([]byte, 0, LeaseHeaderSize+len(md))
+	data = append(data, l.Signature...)
+	data = binary.BigEndian.AppendUint32(data, uint32(created))
+	data = binary.BigEndian.AppendUint32(data, uint32(duration))
+	data = append(data, md...)
+
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (l *Lease) UnmarshalBinary(data []byte) error {
+	lease, err := ParseLease(data)
+	if err != nil {
+		return err
+	}
+	*l = *lease
+	return nil
+}
+
 // CreateLeaseReq is used to create a nw Lease.
 type CreateLeaseReq struct{}
 
@@ -50,7 +260,8 @@ func (c *Client) CreateLease(ctx context.Context, _ *CreateLeaseReq) (*Lease, er
 		request.WithSetHeader("Content-Type", "application/json"),
 	}
 
-	req, err := request.NewHTTP(ctx, http.MethodPost, u.String(), nil, options...)
+	// NOTE: creating a lease has no side effects so it's safe to retry it
+	req, err := request.NewHTTP(client.RetrySafe(ctx), http.MethodPost, u.String(), nil, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -65,26 +276,8 @@ func (c *Client) CreateLease(ctx context.Context, _ *CreateLeaseReq) (*Lease, er
 	if err != nil {
 		return nil, err
 	}
-	var created, duration int32
-	buf := bytes.NewReader(data[64:68])
-	if err := binary.Read(buf, binary.BigEndian, &created); err != nil {
-		return nil, fmt.Errorf("failed reading created data: %v", err)
-	}
-	buf = bytes.NewReader(data[68:72])
-	if err := binary.Read(buf, binary.BigEndian, &duration); err != nil {
-		return nil, fmt.Errorf("failed reading duration data: %v", err)
-	}
-	md := map[string]any{}
-	if err := json.Unmarshal(data[72:], &md); err != nil {
-		return nil, fmt.Errorf("failed reading lease metadata: %v", err)
-	}
 
-	return &Lease{
-		Data:     data,
-		Created:  time.Unix(HTEpoch, 0).Add(time.Duration(created) * time.Second),
-		Duration: time.Duration(duration) * time.Second,
-		Metadata: md,
-	}, nil
+	return ParseLease(data)
 }
 
 // RefreshLease refreshes the existing Lease and returns it.
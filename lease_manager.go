@@ -0,0 +1,336 @@
+package playht
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLeaseRefreshBefore is the default time before the lease expiry
+	// at which the LeaseManager attempts to refresh the lease.
+	DefaultLeaseRefreshBefore = 5 * time.Minute
+	// DefaultLeaseRefreshJitter is the default maximum random jitter
+	// applied to the lease refresh time.
+	DefaultLeaseRefreshJitter = 30 * time.Second
+	// DefaultLeaseRetryInterval is the default interval between
+	// failed background lease refresh attempts.
+	DefaultLeaseRetryInterval = 10 * time.Second
+)
+
+var (
+	// ErrLeaseManagerClosed is returned when a lease is requested from a closed LeaseManager.
+	ErrLeaseManagerClosed = errors.New("lease manager closed")
+)
+
+// LeaseFunc obtains a new Lease.
+type LeaseFunc func(context.Context) (*Lease, error)
+
+// LeaseManagerOptions configure the LeaseManager.
+type LeaseManagerOptions struct {
+	// RefreshBefore is the time before the lease
+	// expiry at which the lease gets refreshed.
+	RefreshBefore time.Duration
+	// RefreshJitter is the maximum random jitter subtracted
+	// from the refresh time so that many clients sharing
+	// the same credentials don't refresh all at once.
+	RefreshJitter time.Duration
+	// RetryInterval is the interval between failed
+	// background lease refresh attempts.
+	RetryInterval time.Duration
+	// Store is consulted before obtaining a new lease.
+	// Newly obtained leases are put into it.
+	Store LeaseStore
+	// StoreKey is the key the lease is stored under.
+	StoreKey string
+}
+
+// LeaseManagerOption is a LeaseManager functional option.
+type LeaseManagerOption func(*LeaseManagerOptions)
+
+// LeaseManager manages the gRPC streaming lease.
+// It obtains a lease lazily when it's first requested and then keeps
+// refreshing it in a background goroutine ahead of its expiry.
+// LeaseManager is safe for concurrent use.
+type LeaseManager struct {
+	fn   LeaseFunc
+	opts LeaseManagerOptions
+
+	mu     sync.RWMutex
+	lease  *Lease
+	closed bool
+	// invalid is the data of the last invalidated lease
+	// which must not be reused from the store.
+	invalid []byte
+
+	// fetch serializes lease fetching.
+	fetch   sync.Mutex
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewLeaseManager creates a new LeaseManager which obtains leases using fn and returns it.
+// It does not obtain any lease until one is requested via Lease.
+func NewLeaseManager(fn LeaseFunc, opts ...LeaseManagerOption) *LeaseManager {
+	options := LeaseManagerOptions{
+		RefreshBefore: DefaultLeaseRefreshBefore,
+		RefreshJitter: DefaultLeaseRefreshJitter,
+		RetryInterval: DefaultLeaseRetryInterval,
+	}
+
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &LeaseManager{
+		fn:     fn,
+		opts:   options,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// WithRefreshBefore sets the time before the lease expiry at which the lease gets refreshed.
+func WithRefreshBefore(d time.Duration) LeaseManagerOption {
+	return func(o *LeaseManagerOptions) {
+		o.RefreshBefore = d
+	}
+}
+
+// WithRefreshJitter sets the maximum lease refresh jitter.
+func WithRefreshJitter(d time.Duration) LeaseManagerOption {
+	return func(o *LeaseManagerOptions) {
+		o.RefreshJitter = d
+	}
+}
+
+// WithRetryInterval sets the interval between failed background lease refresh attempts.
+func WithRetryInterval(d time.Duration) LeaseManagerOption {
+	return func(o *LeaseManagerOptions) {
+		o.RetryInterval = d
+	}
+}
+
+// WithStore sets the store the lease is shared through under the given key.
+func WithStore(store LeaseStore, key string) LeaseManagerOption {
+	return func(o *LeaseManagerOptions) {
+		o.Store = store
+		o.StoreKey = key
+	}
+}
+
+// Lease returns the current valid lease.
+// If there is no valid lease a new one is obtained and the background
+// refresh is started if it hasn't been started already.
+func (m *LeaseManager) Lease(ctx context.Context) (*Lease, error) {
+	if lease, err := m.current(); lease != nil || err != nil {
+		return lease, err
+	}
+
+	m.fetch.Lock()
+	defer m.fetch.Unlock()
+
+	// the lease might have been obtained while we were waiting
+	if lease, err := m.current(); lease != nil || err != nil {
+		return lease, err
+	}
+
+	lease, err := m.obtain(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	m.set(lease)
+
+	if !m.started {
+		m.started = true
+		m.wg.Add(1)
+		go m.run()
+	}
+
+	return lease, nil
+}
+
+// Invalidate drops the current lease so that
+// the next call to Lease obtains a new one.
+// The dropped lease is never reused from the store.
+func (m *LeaseManager) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lease != nil {
+		m.invalid = m.lease.Data
+	}
+	m.lease = nil
+}
+
+// Close stops the background lease refresh.
+// Once closed, the LeaseManager can no longer be used.
+func (m *LeaseManager) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+func (m *LeaseManager) current() (*Lease, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, ErrLeaseManagerClosed
+	}
+	if m.lease != nil && m.lease.Valid(time.Now()) {
+		return m.lease, nil
+	}
+	return nil, nil
+}
+
+func (m *LeaseManager) set(lease *Lease) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lease = lease
+}
+
+func (m *LeaseManager) refresh(ctx context.Context) (*Lease, error) {
+	m.fetch.Lock()
+	defer m.fetch.Unlock()
+
+	// NOTE: the lease being refreshed must not be reused from the store
+	// as it's still valid until the refresh threshold passes, which would
+	// make the refresh loop spin until then, defeating the refresh jitter.
+	var stale []byte
+	m.mu.RLock()
+	if m.lease != nil {
+		stale = m.lease.Data
+	}
+	m.mu.RUnlock()
+
+	lease, err := m.obtain(ctx, stale)
+	if err != nil {
+		return nil, err
+	}
+	m.set(lease)
+	return lease, nil
+}
+
+// obtain returns the lease from the store if there is one
+// which doesn't need refreshing, otherwise it obtains a new
+// lease and puts it in the store. The stored lease is never reused
+// if its data equals stale.
+// NOTE: store errors are not returned as the lease
+// can always be obtained without the store.
+func (m *LeaseManager) obtain(ctx context.Context, stale []byte) (*Lease, error) {
+	store, key := m.opts.Store, m.opts.StoreKey
+	if store == nil {
+		return m.fn(ctx)
+	}
+
+	if locker, ok := store.(LeaseLocker); ok {
+		unlock, err := locker.Lock(ctx, key)
+		switch {
+		case err == nil:
+			defer unlock() // nolint:errcheck
+		case errors.Is(err, errors.ErrUnsupported):
+			// NOTE: the store can still be used without locking
+		default:
+			return nil, err
+		}
+	}
+
+	if lease, err := store.Get(ctx, key); err == nil && m.reusable(lease, stale) {
+		return lease, nil
+	}
+
+	lease, err := m.fn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_ = store.Put(ctx, key, lease)
+
+	return lease, nil
+}
+
+// reusable returns true if the stored lease can be used
+// without having to refresh it straight away.
+func (m *LeaseManager) reusable(lease *Lease, stale []byte) bool {
+	m.mu.RLock()
+	invalid := m.invalid
+	m.mu.RUnlock()
+	if len(invalid) > 0 && bytes.Equal(lease.Data, invalid) {
+		return false
+	}
+	if len(stale) > 0 && bytes.Equal(lease.Data, stale) {
+		return false
+	}
+	return lease.RemainingTTL() > m.refreshBefore(lease)
+}
+
+// run refreshes the lease ahead of its expiry until the manager is closed.
+func (m *LeaseManager) run() {
+	defer m.wg.Done()
+
+	m.mu.RLock()
+	delay := m.refreshDelay(m.lease)
+	m.mu.RUnlock()
+
+	for {
+		t := time.NewTimer(delay)
+		select {
+		case <-m.ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		lease, err := m.refresh(m.ctx)
+		if err != nil {
+			delay = m.opts.RetryInterval
+			continue
+		}
+		delay = m.refreshDelay(lease)
+	}
+}
+
+// refreshDelay returns the time to wait before refreshing the lease.
+func (m *LeaseManager) refreshDelay(lease *Lease) time.Duration {
+	if lease == nil {
+		return 0
+	}
+	if lease.Duration <= 0 {
+		return m.opts.RetryInterval
+	}
+
+	before := m.refreshBefore(lease)
+	jitter := m.opts.RefreshJitter
+	if jitter > before/2 {
+		jitter = before / 2
+	}
+	if jitter > 0 {
+		before += rand.N(jitter)
+	}
+
+	delay := time.Until(lease.Expires()) - before
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// refreshBefore returns the time before the lease expiry at which the lease gets refreshed.
+func (m *LeaseManager) refreshBefore(lease *Lease) time.Duration {
+	before := m.opts.RefreshBefore
+	// NOTE: short-lived leases would otherwise be refreshed in a tight loop
+	if before >= lease.Duration {
+		before = lease.Duration / 2
+	}
+	return before
+}
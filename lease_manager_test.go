@@ -0,0 +1,93 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLeaseFunc(d time.Duration, calls *atomic.Int32) LeaseFunc {
+	return func(context.Context) (*Lease, error) {
+		calls.Add(1)
+		return &Lease{
+			Data:     []byte("lease"),
+			Created:  time.Now(),
+			Duration: d,
+		}, nil
+	}
+}
+
+func TestLeaseManager(t *testing.T) {
+	t.Parallel()
+	t.Run("lazy lease", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		m := NewLeaseManager(newTestLeaseFunc(time.Hour, &calls))
+		defer m.Close()
+		assert.Equal(t, int32(0), calls.Load())
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lease, err := m.Lease(context.Background())
+				assert.NoError(t, err)
+				assert.NotNil(t, lease)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("background refresh", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		m := NewLeaseManager(newTestLeaseFunc(100*time.Millisecond, &calls),
+			WithRefreshBefore(50*time.Millisecond),
+			WithRefreshJitter(0),
+		)
+		defer m.Close()
+		_, err := m.Lease(context.Background())
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return calls.Load() >= 3
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("lease error", func(t *testing.T) {
+		t.Parallel()
+		leaseErr := errors.New("lease error")
+		m := NewLeaseManager(func(context.Context) (*Lease, error) {
+			return nil, leaseErr
+		})
+		defer m.Close()
+		_, err := m.Lease(context.Background())
+		assert.ErrorIs(t, err, leaseErr)
+	})
+	t.Run("invalidate", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		m := NewLeaseManager(newTestLeaseFunc(time.Hour, &calls))
+		defer m.Close()
+		_, err := m.Lease(context.Background())
+		assert.NoError(t, err)
+		m.Invalidate()
+		_, err = m.Lease(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		m := NewLeaseManager(newTestLeaseFunc(time.Hour, &calls))
+		_, err := m.Lease(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, m.Close())
+		_, err = m.Lease(context.Background())
+		assert.ErrorIs(t, err, ErrLeaseManagerClosed)
+	})
+}
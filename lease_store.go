@@ -0,0 +1,204 @@
+package playht
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// FileLeaseStorePerm is the FileLeaseStore directory permission.
+	FileLeaseStorePerm = 0o700
+)
+
+var (
+	// ErrLeaseNotFound is returned by LeaseStore when there is no valid lease stored.
+	ErrLeaseNotFound = errors.New("lease not found")
+)
+
+// LeaseStore stores leases so they can be reused by multiple clients.
+type LeaseStore interface {
+	// Get returns the lease stored under key.
+	// It returns ErrLeaseNotFound if there is no lease
+	// stored under key or if the stored lease has expired.
+	Get(ctx context.Context, key string) (*Lease, error)
+	// Put stores the lease under key until the lease expires.
+	Put(ctx context.Context, key string, lease *Lease) error
+}
+
+// LeaseLocker is implemented by LeaseStores which can serialize
+// obtaining new leases across multiple clients or processes.
+type LeaseLocker interface {
+	// Lock blocks until the lock for key is acquired or ctx is done.
+	// The returned func releases the lock. If locking is not supported,
+	// it returns an error matching errors.ErrUnsupported and the lease
+	// is obtained without locking.
+	Lock(ctx context.Context, key string) (func() error, error)
+}
+
+// MemLeaseStore is an in-memory LeaseStore.
+// It can be used to share a lease between multiple clients in the same process.
+type MemLeaseStore struct {
+	mu     sync.RWMutex
+	leases map[string]*Lease
+	locks  map[string]chan struct{}
+}
+
+// NewMemLeaseStore creates a new in-memory lease store and returns it.
+func NewMemLeaseStore() *MemLeaseStore {
+	return &MemLeaseStore{
+		leases: make(map[string]*Lease),
+		locks:  make(map[string]chan struct{}),
+	}
+}
+
+// Get implements LeaseStore.
+func (s *MemLeaseStore) Get(_ context.Context, key string) (*Lease, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lease, ok := s.leases[key]
+	if !ok || !lease.Valid(time.Now()) {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
+
+// Put implements LeaseStore.
+func (s *MemLeaseStore) Put(_ context.Context, key string, lease *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases[key] = lease
+	return nil
+}
+
+// Lock implements LeaseLocker.
+func (s *MemLeaseStore) Lock(ctx context.Context, key string) (func() error, error) {
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.locks[key] = lock
+	}
+	s.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return func() error {
+		<-lock
+		return nil
+	}, nil
+}
+
+// FileLeaseStore is a LeaseStore which stores leases in files in a directory.
+// It can be used to share a lease between multiple processes which have access
+// to the same directory. Leases are written atomically and obtaining new leases
+// is serialized across processes via file locks.
+// NOTE: file locking is only supported on unix systems. Elsewhere Lock returns
+// an error matching errors.ErrUnsupported and LeaseManager obtains the leases
+// without any locking, so multiple processes may obtain a new lease at once.
+type FileLeaseStore struct {
+	dir string
+}
+
+// NewFileLeaseStore creates a new file lease store in dir and returns it.
+// The directory is created if it does not exist.
+func NewFileLeaseStore(dir string) (*FileLeaseStore, error) {
+	if err := os.MkdirAll(dir, FileLeaseStorePerm); err != nil {
+		return nil, err
+	}
+	return &FileLeaseStore{
+		dir: dir,
+	}, nil
+}
+
+// Get implements LeaseStore.
+func (s *FileLeaseStore) Get(_ context.Context, key string) (*Lease, error) {
+	data, err := os.ReadFile(s.path(key, ".lease"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrLeaseNotFound
+		}
+		return nil, err
+	}
+
+	lease, err := ParseLease(data)
+	if err != nil {
+		return nil, err
+	}
+	if !lease.Valid(time.Now()) {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
+
+// Put implements LeaseStore.
+func (s *FileLeaseStore) Put(_ context.Context, key string, lease *Lease) error {
+	data, err := lease.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, ".lease-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// NOTE: rename is atomic so readers never see partially written leases
+	return os.Rename(f.Name(), s.path(key, ".lease"))
+}
+
+// Lock implements LeaseLocker.
+// It returns an error matching errors.ErrUnsupported on non-unix systems.
+func (s *FileLeaseStore) Lock(ctx context.Context, key string) (func() error, error) {
+	f, err := os.OpenFile(s.path(key, ".lock"), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	return func() error {
+		// NOTE: closing the file releases the lock
+		return f.Close()
+	}, nil
+}
+
+// path returns the path of the file storing key data.
+// The key is hashed so it's safe to use it as a file name.
+func (s *FileLeaseStore) path(key, ext string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+ext)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package playht
+
+import (
+	"errors"
+	"os"
+)
+
+// tryLockFile is not supported on systems without flock support.
+func tryLockFile(*os.File) (bool, error) {
+	return false, errors.ErrUnsupported
+}
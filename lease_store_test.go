@@ -0,0 +1,208 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const leaseStoreDirEnv = "PLAYHT_TEST_LEASE_STORE_DIR"
+
+func newTestStoreLeaseFunc(calls *atomic.Int32) LeaseFunc {
+	return func(context.Context) (*Lease, error) {
+		n := calls.Add(1)
+		created := uint32(time.Now().Unix() - HTEpoch)
+		return ParseLease(makeTestLease(created, 3600, fmt.Sprintf(`{"n":%d}`, n)))
+	}
+}
+
+func TestLeaseStore(t *testing.T) {
+	t.Parallel()
+	fileStore, err := NewFileLeaseStore(t.TempDir())
+	assert.NoError(t, err)
+
+	stores := map[string]LeaseStore{
+		"mem":  NewMemLeaseStore(),
+		"file": fileStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			_, err := store.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrLeaseNotFound)
+
+			expired, err := ParseLease(makeTestLease(100, 3600, `{}`))
+			assert.NoError(t, err)
+			assert.NoError(t, store.Put(ctx, "key", expired))
+			_, err = store.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrLeaseNotFound)
+
+			var calls atomic.Int32
+			lease, err := newTestStoreLeaseFunc(&calls)(ctx)
+			assert.NoError(t, err)
+			assert.NoError(t, store.Put(ctx, "key", lease))
+			got, err := store.Get(ctx, "key")
+			assert.NoError(t, err)
+			assert.Equal(t, lease.Data, got.Data)
+		})
+	}
+}
+
+func TestLeaseManagerStore(t *testing.T) {
+	t.Parallel()
+	store := NewMemLeaseStore()
+
+	var calls atomic.Int32
+	m1 := NewLeaseManager(newTestStoreLeaseFunc(&calls), WithStore(store, "key"))
+	defer m1.Close()
+	m2 := NewLeaseManager(newTestStoreLeaseFunc(&calls), WithStore(store, "key"))
+	defer m2.Close()
+
+	l1, err := m1.Lease(context.Background())
+	assert.NoError(t, err)
+	l2, err := m2.Lease(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, l1.Data, l2.Data)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// invalidated lease must not be reused from the store
+	m2.Invalidate()
+	l2, err = m2.Lease(context.Background())
+	assert.NoError(t, err)
+	assert.NotEqual(t, l1.Data, l2.Data)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+// TestFileLeaseStoreProcesses runs two processes sharing the same
+// lease store directory and checks only one of them creates the lease.
+func TestFileLeaseStoreProcesses(t *testing.T) {
+	t.Parallel()
+	if os.Getenv(leaseStoreDirEnv) != "" {
+		t.Skip("helper process")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("file locking is not supported")
+	}
+
+	dir := t.TempDir()
+	cmds := make([]*exec.Cmd, 2)
+	for i := range cmds {
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^TestFileLeaseStoreHelper$", "-test.count=1")
+		cmds[i].Env = append(os.Environ(), leaseStoreDirEnv+"="+dir)
+		assert.NoError(t, cmds[i].Start())
+	}
+	for _, cmd := range cmds {
+		assert.NoError(t, cmd.Wait())
+	}
+
+	created, err := os.ReadFile(filepath.Join(dir, "created"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(created), "\n"))
+}
+
+func TestFileLeaseStoreHelper(t *testing.T) {
+	dir := os.Getenv(leaseStoreDirEnv)
+	if dir == "" {
+		t.Skip("not a helper process")
+	}
+
+	store, err := NewFileLeaseStore(dir)
+	assert.NoError(t, err)
+
+	var calls atomic.Int32
+	create := newTestStoreLeaseFunc(&calls)
+	m := NewLeaseManager(func(ctx context.Context) (*Lease, error) {
+		f, err := os.OpenFile(filepath.Join(dir, "created"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if _, err := f.WriteString("lease\n"); err != nil {
+			return nil, err
+		}
+		// give the other process a chance to race us
+		time.Sleep(100 * time.Millisecond)
+		return create(ctx)
+	}, WithStore(store, "key"))
+	defer m.Close()
+
+	_, err = m.Lease(context.Background())
+	assert.NoError(t, err)
+}
+
+// countingStore counts the Get calls.
+type countingStore struct {
+	*MemLeaseStore
+	gets atomic.Int32
+}
+
+func (s *countingStore) Get(ctx context.Context, key string) (*Lease, error) {
+	s.gets.Add(1)
+	return s.MemLeaseStore.Get(ctx, key)
+}
+
+func TestLeaseManagerStoreRefresh(t *testing.T) {
+	t.Parallel()
+	store := &countingStore{MemLeaseStore: NewMemLeaseStore()}
+
+	var calls atomic.Int32
+	fn := func(context.Context) (*Lease, error) {
+		n := calls.Add(1)
+		return &Lease{
+			Data:     fmt.Appendf(nil, "lease-%d", n),
+			Created:  time.Now(),
+			Duration: 600 * time.Millisecond,
+		}, nil
+	}
+	m := NewLeaseManager(fn,
+		WithStore(store, "key"),
+		WithRefreshBefore(300*time.Millisecond),
+		WithRefreshJitter(150*time.Millisecond),
+	)
+	defer m.Close()
+
+	_, err := m.Lease(context.Background())
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return calls.Load() >= 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the refreshed lease must not be reused from the store
+	// which would make the refresh loop spin until the jitter passes
+	assert.LessOrEqual(t, store.gets.Load(), calls.Load()+1)
+}
+
+// unsupportedLockStore does not support locking.
+type unsupportedLockStore struct {
+	*MemLeaseStore
+}
+
+func (s *unsupportedLockStore) Lock(context.Context, string) (func() error, error) {
+	return nil, fmt.Errorf("lock: %w", errors.ErrUnsupported)
+}
+
+func TestLeaseManagerStoreLockUnsupported(t *testing.T) {
+	t.Parallel()
+	store := &unsupportedLockStore{MemLeaseStore: NewMemLeaseStore()}
+
+	var calls atomic.Int32
+	m := NewLeaseManager(newTestStoreLeaseFunc(&calls), WithStore(store, "key"))
+	defer m.Close()
+
+	lease, err := m.Lease(context.Background())
+	assert.NoError(t, err)
+	stored, err := store.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, lease.Data, stored.Data)
+}
@@ -0,0 +1,138 @@
+package playht
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestLease(created, duration uint32, md string) []byte {
+	data := bytes.Repeat([]byte{0xab}, LeaseSignatureSize)
+	data = binary.BigEndian.AppendUint32(data, created)
+	data = binary.BigEndian.AppendUint32(data, duration)
+	return append(data, md...)
+}
+
+func TestParseLease(t *testing.T) {
+	t.Parallel()
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		data := makeTestLease(100, 3600, `{"foo":"bar"}`)
+		lease, err := ParseLease(data)
+		assert.NoError(t, err)
+		assert.Equal(t, data, lease.Data)
+		assert.Equal(t, data[:LeaseSignatureSize], lease.Signature)
+		assert.Equal(t, time.Unix(HTEpoch+100, 0), lease.Created)
+		assert.Equal(t, time.Hour, lease.Duration)
+		assert.Equal(t, "bar", lease.Metadata.Raw["foo"])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			name string
+			data []byte
+		}{
+			{"empty", nil},
+			{"short header", makeTestLease(100, 3600, "")[:LeaseHeaderSize-1]},
+			{"missing metadata", makeTestLease(100, 3600, "")},
+			{"malformed metadata", makeTestLease(100, 3600, "{")},
+			{"negative duration", makeTestLease(100, 0xffffffff, "{}")},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := ParseLease(tc.data)
+				assert.ErrorIs(t, err, ErrInvalidLease)
+			})
+		}
+	})
+}
+
+func TestLeaseBinary(t *testing.T) {
+	t.Parallel()
+	t.Run("parsed", func(t *testing.T) {
+		t.Parallel()
+		data := makeTestLease(100, 3600, `{"b":1,"a":2}`)
+		lease, err := ParseLease(data)
+		assert.NoError(t, err)
+		b, err := lease.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, data, b)
+	})
+	t.Run("fields", func(t *testing.T) {
+		t.Parallel()
+		lease := &Lease{
+			Signature: bytes.Repeat([]byte{0xab}, LeaseSignatureSize),
+			Created:   time.Unix(HTEpoch+100, 0),
+			Duration:  time.Hour,
+			Metadata:  LeaseMetadata{Raw: map[string]any{"foo": "bar"}},
+		}
+		b, err := lease.MarshalBinary()
+		assert.NoError(t, err)
+		assert.Equal(t, makeTestLease(100, 3600, `{"foo":"bar"}`), b)
+
+		var got Lease
+		assert.NoError(t, got.UnmarshalBinary(b))
+		assert.Equal(t, lease.Created, got.Created)
+		assert.Equal(t, lease.Duration, got.Duration)
+		assert.Equal(t, lease.Metadata, got.Metadata)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		t.Parallel()
+		lease := &Lease{Signature: []byte{0xab}}
+		_, err := lease.MarshalBinary()
+		assert.ErrorIs(t, err, ErrInvalidLease)
+	})
+}
+
+func TestLeaseMetadata(t *testing.T) {
+	t.Parallel()
+	t.Run("typed", func(t *testing.T) {
+		t.Parallel()
+		md := `{"user_id":"user","plan":"pro","inference_address":"foo:443","limits":{"concurrency":2},"unknown":true}`
+		lease, err := ParseLease(makeTestLease(100, 3600, md))
+		assert.NoError(t, err)
+		assert.Equal(t, "user", lease.Metadata.UserID)
+		assert.Equal(t, "pro", lease.Metadata.Plan)
+		assert.Equal(t, 2, lease.Metadata.Limits.Concurrency)
+		assert.Equal(t, true, lease.Metadata.Raw["unknown"])
+		assert.Equal(t, "foo:443", lease.Metadata.GrpcAddr(true))
+	})
+	t.Run("malformed field", func(t *testing.T) {
+		t.Parallel()
+		lease, err := ParseLease(makeTestLease(100, 3600, `{"user_id":1,"plan":"pro"}`))
+		assert.NoError(t, err)
+		assert.Empty(t, lease.Metadata.UserID)
+		assert.Equal(t, "pro", lease.Metadata.Plan)
+		assert.Equal(t, GrpcAddr, lease.Metadata.GrpcAddr(false))
+	})
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		md := LeaseMetadata{
+			Plan: "pro",
+			Raw:  map[string]any{"plan": "free", "unknown": true},
+		}
+		b, err := json.Marshal(md)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"plan":"pro","unknown":true}`, string(b))
+	})
+}
+
+func TestLeaseValid(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	lease := &Lease{
+		Data:     []byte("lease"),
+		Created:  now.Add(-time.Minute),
+		Duration: time.Hour,
+	}
+	assert.True(t, lease.Valid(now))
+	assert.False(t, lease.Valid(now.Add(time.Hour)))
+	assert.InDelta(t, float64(59*time.Minute), float64(lease.RemainingTTL()), float64(time.Second))
+	assert.False(t, (&Lease{Duration: time.Hour, Created: now}).Valid(now))
+	assert.Zero(t, (&Lease{}).RemainingTTL())
+}
@@ -0,0 +1,364 @@
+package playht
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+)
+
+// TTSParams are the canonical TTS parameters.
+// They can be converted to both HTTP request bodies and gRPC TtsParams.
+// Optional parameters are pointers: nil parameters are not sent
+// and the API uses their default values instead.
+type TTSParams struct {
+	Text string `json:"text"`
+	// TextParts are the text parts sent over gRPC instead of Text.
+	// Text should contain the parts joined with a space.
+	TextParts    []string     `json:"-"`
+	Voice        string       `json:"voice"`
+	Quality      Quality      `json:"quality,omitempty"`
+	OutputFormat OutputFormat `json:"output_format,omitempty"`
+	VoiceEngine  VoiceEngine  `json:"voice_engine,omitempty"`
+	Emotion      Emotion      `json:"emotion,omitempty"`
+	SampleRate   *int32       `json:"sample_rate,omitempty"`
+	Speed        *float32     `json:"speed,omitempty"`
+	Seed         *int32       `json:"seed,omitempty"`
+	Temperature  *float32     `json:"temperature,omitempty"`
+	TopP         *float32     `json:"top_p,omitempty"`
+	// StyleGuidance is only supported by PlayHT2.0 voice engine.
+	StyleGuidance *float32 `json:"style_guidance,omitempty"`
+	VoiceGuidance *float32 `json:"voice_guidance,omitempty"`
+	TextGuidance  *float32 `json:"text_guidance,omitempty"`
+	// AudioSource, SpeakerAttributes, SpeechAttributes and LanguageIdentifier
+	// are the low level model parameters which are only supported over gRPC.
+	AudioSource        *int32 `json:"audio_source,omitempty"`
+	SpeakerAttributes  *int32 `json:"speaker_attributes,omitempty"`
+	SpeechAttributes   *int32 `json:"speech_attributes,omitempty"`
+	LanguageIdentifier *int32 `json:"language_identifier,omitempty"`
+	// Other contains any other parameters.
+	// They're sent in the gRPC TtsParams Other JSON string
+	// and merged into the HTTP request JSON body.
+	Other map[string]any `json:"-"`
+}
+
+// ttsParams is used to avoid recursive JSON (un)marshaling.
+type ttsParams TTSParams
+
+// MarshalJSON implements json.Marshaler.
+// The known parameters take precedence over the parameters in Other.
+func (p TTSParams) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(ttsParams(p))
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Other) == 0 {
+		return b, nil
+	}
+
+	known := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+	params := make(map[string]any, len(p.Other)+len(known))
+	for key, val := range p.Other {
+		params[key] = val
+	}
+	for key, val := range known {
+		params[key] = val
+	}
+	return json.Marshal(params)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Unknown parameters are stored in Other.
+func (p *TTSParams) UnmarshalJSON(data []byte) error {
+	params := ttsParams{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	other := map[string]any{}
+	if err := json.Unmarshal(data, &other); err != nil {
+		return err
+	}
+	for _, key := range ttsParamsKeys {
+		delete(other, key)
+	}
+	if len(other) > 0 {
+		params.Other = other
+	}
+
+	*p = TTSParams(params)
+	return nil
+}
+
+// ttsParamsKeys are the JSON keys of the known TTSParams.
+var ttsParamsKeys = []string{
+	"text", "voice", "quality", "output_format", "voice_engine", "emotion",
+	"sample_rate", "speed", "seed", "temperature", "top_p",
+	"style_guidance", "voice_guidance", "text_guidance",
+	"audio_source", "speaker_attributes", "speech_attributes", "language_identifier",
+}
+
+// StreamReq returns the TTS stream request created from the params.
+// NOTE: TopP, model parameters and Other are not supported by CreateTTSStreamReq;
+// HTTPSynthesizer sends the params as they are instead.
+func (p *TTSParams) StreamReq() *CreateTTSStreamReq {
+	return &CreateTTSStreamReq{
+		Text:          p.Text,
+		Voice:         p.Voice,
+		Quality:       p.Quality,
+		OutputFormat:  p.OutputFormat,
+		VoiceEngine:   p.VoiceEngine,
+		Emotion:       p.Emotion,
+		SampleRate:    deref(p.SampleRate),
+		Seed:          deref(p.Seed),
+		VoiceGuidance: deref(p.VoiceGuidance),
+		StyleGuidance: deref(p.StyleGuidance),
+		TextGuidance:  deref(p.TextGuidance),
+		Temperature:   deref(p.Temperature),
+		Speed:         deref(p.Speed),
+	}
+}
+
+// JobReq returns the TTS job request created from the params.
+// TTS jobs only support seeds in the uint8 range; it returns *ValidationError
+// if the seed is out of range rather than truncating it.
+// NOTE: TTS jobs don't support TextGuidance, TopP, model parameters and Other.
+func (p *TTSParams) JobReq() (*CreateTTSJobReq, error) {
+	if p.Seed != nil && (*p.Seed < 0 || *p.Seed > math.MaxUint8) {
+		return nil, &ValidationError{Fields: []FieldError{{
+			Field:  "seed",
+			Value:  *p.Seed,
+			Reason: fmt.Sprintf("must be in range [0, %d] for TTS jobs", math.MaxUint8),
+		}}}
+	}
+
+	return &CreateTTSJobReq{
+		Text:          p.Text,
+		Voice:         p.Voice,
+		Quality:       p.Quality,
+		OutputFormat:  p.OutputFormat,
+		VoiceEngine:   p.VoiceEngine,
+		Emotion:       p.Emotion,
+		Speed:         deref(p.Speed),
+		Temperature:   deref(p.Temperature),
+		SampleRate:    deref(p.SampleRate),
+		Seed:          uint8(deref(p.Seed)),
+		VoiceGuidance: deref(p.VoiceGuidance),
+		StyleGuidance: deref(p.StyleGuidance),
+	}, nil
+}
+
+// StreamReqParams returns TTSParams created from the TTS stream request.
+// Zero values are treated as unset.
+func StreamReqParams(req *CreateTTSStreamReq) *TTSParams {
+	return &TTSParams{
+		Text:          req.Text,
+		Voice:         req.Voice,
+		Quality:       req.Quality,
+		OutputFormat:  req.OutputFormat,
+		VoiceEngine:   req.VoiceEngine,
+		Emotion:       req.Emotion,
+		SampleRate:    nonZero(req.SampleRate),
+		Speed:         nonZero(req.Speed),
+		Seed:          nonZero(req.Seed),
+		Temperature:   nonZero(req.Temperature),
+		StyleGuidance: nonZero(req.StyleGuidance),
+		VoiceGuidance: nonZero(req.VoiceGuidance),
+		TextGuidance:  nonZero(req.TextGuidance),
+	}
+}
+
+// JobReqParams returns TTSParams created from the TTS job request.
+// Zero values are treated as unset.
+func JobReqParams(req *CreateTTSJobReq) *TTSParams {
+	return &TTSParams{
+		Text:          req.Text,
+		Voice:         req.Voice,
+		Quality:       req.Quality,
+		OutputFormat:  req.OutputFormat,
+		VoiceEngine:   req.VoiceEngine,
+		Emotion:       req.Emotion,
+		SampleRate:    nonZero(req.SampleRate),
+		Speed:         nonZero(req.Speed),
+		Seed:          nonZero(int32(req.Seed)),
+		Temperature:   nonZero(req.Temperature),
+		StyleGuidance: nonZero(req.StyleGuidance),
+		VoiceGuidance: nonZero(req.VoiceGuidance),
+	}
+}
+
+// ToPbParams converts TTSParams to its proto representation.
+// VoiceEngine and Emotion have no proto fields so they're
+// sent along with Other in the proto Other JSON string.
+func ToPbParams(p *TTSParams) (*pb.TtsParams, error) {
+	text := []string{p.Text}
+	if len(p.TextParts) > 0 {
+		text = slices.Clone(p.TextParts)
+	}
+
+	params := &pb.TtsParams{
+		Text:               text,
+		Voice:              p.Voice,
+		Quality:            ToPbQuality(p.Quality),
+		Format:             ToPbFormat(p.OutputFormat),
+		SampleRate:         clone(p.SampleRate),
+		Speed:              clone(p.Speed),
+		Seed:               clone(p.Seed),
+		Temperature:        clone(p.Temperature),
+		TopP:               clone(p.TopP),
+		StyleGuidance:      clone(p.StyleGuidance),
+		VoiceGuidance:      clone(p.VoiceGuidance),
+		TextGuidance:       clone(p.TextGuidance),
+		AudioSource:        clone(p.AudioSource),
+		SpeakerAttributes:  clone(p.SpeakerAttributes),
+		SpeechAttributes:   clone(p.SpeechAttributes),
+		LanguageIdentifier: clone(p.LanguageIdentifier),
+	}
+
+	other := make(map[string]any, len(p.Other)+2)
+	for key, val := range p.Other {
+		other[key] = val
+	}
+	if p.VoiceEngine != "" {
+		other["voice_engine"] = p.VoiceEngine
+	}
+	if p.Emotion != "" {
+		other["emotion"] = p.Emotion
+	}
+	if len(other) > 0 {
+		b, err := json.Marshal(other)
+		if err != nil {
+			return nil, fmt.Errorf("failed encoding other params: %v", err)
+		}
+		params.Other = StringPtr(string(b))
+	}
+
+	return params, nil
+}
+
+// FromPbParams converts proto TtsParams to TTSParams.
+// Multiple text parts are joined with a space into Text
+// and kept as they are in TextParts.
+// VoiceEngine and Emotion are read from the proto Other JSON string;
+// the remaining Other parameters are stored in Other.
+func FromPbParams(params *pb.TtsParams) (*TTSParams, error) {
+	p := &TTSParams{
+		Text:               strings.Join(params.GetText(), " "),
+		Voice:              params.GetVoice(),
+		Quality:            FromPbQuality(params.Quality),
+		OutputFormat:       FromPbFormat(params.Format),
+		SampleRate:         clone(params.SampleRate),
+		Speed:              clone(params.Speed),
+		Seed:               clone(params.Seed),
+		Temperature:        clone(params.Temperature),
+		TopP:               clone(params.TopP),
+		StyleGuidance:      clone(params.StyleGuidance),
+		VoiceGuidance:      clone(params.VoiceGuidance),
+		TextGuidance:       clone(params.TextGuidance),
+		AudioSource:        clone(params.AudioSource),
+		SpeakerAttributes:  clone(params.SpeakerAttributes),
+		SpeechAttributes:   clone(params.SpeechAttributes),
+		LanguageIdentifier: clone(params.LanguageIdentifier),
+	}
+	if len(params.GetText()) > 1 {
+		p.TextParts = slices.Clone(params.GetText())
+	}
+
+	if params.Other == nil {
+		return p, nil
+	}
+
+	other := map[string]any{}
+	if err := json.Unmarshal([]byte(params.GetOther()), &other); err != nil {
+		return nil, fmt.Errorf("failed decoding other params: %v", err)
+	}
+	if engine, ok := other["voice_engine"].(string); ok {
+		p.VoiceEngine = VoiceEngine(engine)
+		delete(other, "voice_engine")
+	}
+	if emotion, ok := other["emotion"].(string); ok {
+		p.Emotion = Emotion(emotion)
+		delete(other, "emotion")
+	}
+	if len(other) > 0 {
+		p.Other = other
+	}
+
+	return p, nil
+}
+
+// FromPbQuality converts proto Quality to Quality.
+func FromPbQuality(q *pb.Quality) Quality {
+	if q == nil {
+		return ""
+	}
+	switch *q {
+	case pb.Quality_QUALITY_DRAFT:
+		return Draft
+	case pb.Quality_QUALITY_LOW:
+		return Low
+	case pb.Quality_QUALITY_MEDIUM:
+		return Medium
+	case pb.Quality_QUALITY_HIGH:
+		return High
+	case pb.Quality_QUALITY_PREMIUM:
+		return Premium
+	case pb.Quality_QUALITY_UNSPECIFIED:
+		return Unspecified
+	default:
+		return ""
+	}
+}
+
+// FromPbFormat converts proto Format to OutputFormat.
+func FromPbFormat(f *pb.Format) OutputFormat {
+	if f == nil {
+		return ""
+	}
+	switch *f {
+	case pb.Format_FORMAT_MP3:
+		return Mp3
+	case pb.Format_FORMAT_WAV:
+		return Wav
+	case pb.Format_FORMAT_OGG:
+		return Ogg
+	case pb.Format_FORMAT_FLAC:
+		return Flac
+	case pb.Format_FORMAT_MULAW:
+		return Mulaw
+	case pb.Format_FORMAT_RAW:
+		return Raw
+	default:
+		return ""
+	}
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
+
+func clone[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func nonZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
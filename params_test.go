@@ -0,0 +1,195 @@
+package playht
+
+import (
+	"encoding/json"
+	"testing"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTTSParamsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		params *TTSParams
+	}{
+		{
+			name:   "minimal",
+			params: &TTSParams{Text: "hello", Voice: "voice"},
+		},
+		{
+			name: "engine and emotion",
+			params: &TTSParams{
+				Text:        "hello",
+				Voice:       "voice",
+				VoiceEngine: PlayHTv2,
+				Emotion:     FemaleHappy,
+			},
+		},
+		{
+			name: "full",
+			params: &TTSParams{
+				Text:               "hello",
+				Voice:              "voice",
+				Quality:            Premium,
+				OutputFormat:       Flac,
+				VoiceEngine:        PlayHTv2Turbo,
+				SampleRate:         Int32Ptr(44100),
+				Speed:              Float32Ptr(1.5),
+				Seed:               Int32Ptr(0),
+				Temperature:        Float32Ptr(0.5),
+				TopP:               Float32Ptr(0.85),
+				StyleGuidance:      Float32Ptr(10),
+				VoiceGuidance:      Float32Ptr(2),
+				TextGuidance:       Float32Ptr(1),
+				AudioSource:        Int32Ptr(-1),
+				SpeakerAttributes:  Int32Ptr(36),
+				SpeechAttributes:   Int32Ptr(-1),
+				LanguageIdentifier: Int32Ptr(-1),
+				Other:              map[string]any{"foo": "bar"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name+" proto", func(t *testing.T) {
+			t.Parallel()
+			pbParams, err := ToPbParams(tc.params)
+			assert.NoError(t, err)
+			params, err := FromPbParams(pbParams)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.params, params)
+		})
+		t.Run(tc.name+" json", func(t *testing.T) {
+			t.Parallel()
+			b, err := json.Marshal(tc.params)
+			assert.NoError(t, err)
+			params := &TTSParams{}
+			assert.NoError(t, json.Unmarshal(b, params))
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
+
+func TestPbParamsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		params *pb.TtsParams
+	}{
+		{
+			name:   "minimal",
+			params: &pb.TtsParams{Text: []string{"hello"}, Voice: "voice"},
+		},
+		{
+			name: "raw format",
+			params: &pb.TtsParams{
+				Text:   []string{"hello"},
+				Voice:  "voice",
+				Format: formatPtr(pb.Format_FORMAT_RAW),
+			},
+		},
+		{
+			name: "unspecified quality",
+			params: &pb.TtsParams{
+				Text:    []string{"hello"},
+				Voice:   "voice",
+				Quality: qualityPtr(pb.Quality_QUALITY_UNSPECIFIED),
+			},
+		},
+		{
+			name: "text parts",
+			params: &pb.TtsParams{
+				Text:  []string{"hello", "world"},
+				Voice: "voice",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			params, err := FromPbParams(tc.params)
+			assert.NoError(t, err)
+			pbParams, err := ToPbParams(params)
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tc.params, pbParams), "got %v, want %v", pbParams, tc.params)
+		})
+	}
+
+	params, err := FromPbParams(&pb.TtsParams{Text: []string{"hello", "world"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", params.Text)
+	assert.Equal(t, []string{"hello", "world"}, params.TextParts)
+}
+
+func TestFromPbParamsInvalidOther(t *testing.T) {
+	t.Parallel()
+	_, err := FromPbParams(&pb.TtsParams{Other: StringPtr("{")})
+	assert.Error(t, err)
+}
+
+func TestTTSParamsJSON(t *testing.T) {
+	t.Parallel()
+	params := &TTSParams{
+		Text:  "hello",
+		Voice: "voice",
+		Seed:  Int32Ptr(0),
+		Other: map[string]any{"text": "overridden", "foo": 1},
+	}
+	b, err := json.Marshal(params)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text":"hello","voice":"voice","seed":0,"foo":1}`, string(b))
+}
+
+func TestMakeGrpcStreamRequest(t *testing.T) {
+	t.Parallel()
+	req := MakeGrpcStreamRequest([]byte("lease"), &CreateTTSStreamReq{
+		Text:        "hello",
+		Voice:       "voice",
+		VoiceEngine: PlayHTv2,
+		Emotion:     MaleSad,
+		Speed:       1.5,
+	})
+	assert.Equal(t, []byte("lease"), req.Lease)
+	assert.Equal(t, float32(1.5), req.Params.GetSpeed())
+	assert.Nil(t, req.Params.SampleRate)
+	assert.Nil(t, req.Params.Seed)
+	assert.Nil(t, req.Params.Quality)
+	assert.JSONEq(t, `{"voice_engine":"PlayHT2.0","emotion":"male_sad"}`, req.Params.GetOther())
+
+	params, err := FromPbParams(req.Params)
+	assert.NoError(t, err)
+	assert.Equal(t, StreamReqParams(&CreateTTSStreamReq{
+		Text:        "hello",
+		Voice:       "voice",
+		VoiceEngine: PlayHTv2,
+		Emotion:     MaleSad,
+		Speed:       1.5,
+	}), params)
+}
+
+func TestTTSParamsRequests(t *testing.T) {
+	t.Parallel()
+	params := &TTSParams{Text: "hello", Seed: Int32Ptr(7), TextGuidance: Float32Ptr(1)}
+	assert.Equal(t, int32(7), params.StreamReq().Seed)
+	assert.Equal(t, float32(1), params.StreamReq().TextGuidance)
+	jobReq, err := params.JobReq()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(7), jobReq.Seed)
+	assert.Equal(t, &TTSParams{Text: "hello", Seed: Int32Ptr(7)}, JobReqParams(jobReq))
+
+	// seeds out of the uint8 range must not be truncated
+	params.Seed = Int32Ptr(300)
+	_, err = params.JobReq()
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	var validationErr *ValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		_, ok := validationErr.Field("seed")
+		assert.True(t, ok)
+	}
+}
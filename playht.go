@@ -1,6 +1,7 @@
 package playht
 
 import (
+	"context"
 	"os"
 
 	"github.com/milosgajdos/go-playht/client"
@@ -22,7 +23,8 @@ const (
 
 // Client is an OpenAI HTTP API client.
 type Client struct {
-	opts Options
+	opts   Options
+	leases *LeaseManager
 }
 
 type Options struct {
@@ -32,6 +34,11 @@ type Options struct {
 	Version    string
 	HTTPClient *client.HTTP
 	GRPC       *grpc.ClientConn
+	// LeaseOptions configure the client LeaseManager.
+	LeaseOptions []LeaseManagerOption
+	// LeaseStore is used to share the lease between clients.
+	// The lease is stored under the UserID key.
+	LeaseStore LeaseStore
 }
 
 // Option is functional graph option.
@@ -54,9 +61,30 @@ func NewClient(opts ...Option) *Client {
 		apply(&options)
 	}
 
-	return &Client{
+	c := &Client{
 		opts: options,
 	}
+	leaseOpts := options.LeaseOptions
+	if options.LeaseStore != nil {
+		leaseOpts = append([]LeaseManagerOption{WithStore(options.LeaseStore, options.UserID)}, leaseOpts...)
+	}
+	c.leases = NewLeaseManager(func(ctx context.Context) (*Lease, error) {
+		return c.CreateLease(ctx, &CreateLeaseReq{})
+	}, leaseOpts...)
+
+	return c
+}
+
+// Leases returns the client LeaseManager.
+func (c *Client) Leases() *LeaseManager {
+	return c.leases
+}
+
+// Close releases the client resources.
+// It stops the background gRPC lease refresh.
+// NOTE: it does not close the gRPC client connection.
+func (c *Client) Close() error {
+	return c.leases.Close()
 }
 
 // WithSecretKey sets the secret key.
@@ -100,3 +128,17 @@ func WithGRPCClient(c *grpc.ClientConn) Option {
 		o.GRPC = c
 	}
 }
+
+// WithLeaseOptions sets the LeaseManager options.
+func WithLeaseOptions(opts ...LeaseManagerOption) Option {
+	return func(o *Options) {
+		o.LeaseOptions = append(o.LeaseOptions, opts...)
+	}
+}
+
+// WithLeaseStore sets the store used to share the lease between clients.
+func WithLeaseStore(store LeaseStore) Option {
+	return func(o *Options) {
+		o.LeaseStore = store
+	}
+}
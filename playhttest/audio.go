@@ -0,0 +1,387 @@
+package playhttest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+)
+
+const (
+	// DefaultSampleRate is the default generated audio sample rate.
+	// It's the native sample rate of the PlayHT models.
+	DefaultSampleRate = 24000
+	// ToneFrequency is the frequency of the generated tone in Hz.
+	ToneFrequency = 440
+	// flacBlockSize is the number of samples in FLAC frames.
+	flacBlockSize = 4096
+	// mp3FrameSamples is the number of samples in MPEG-1 Layer III frames.
+	mp3FrameSamples = 1152
+	// mp3Bitrate is the generated MP3 bitrate.
+	mp3Bitrate = 128000
+)
+
+// ContentType returns the MIME type of the audio format.
+func ContentType(format playht.OutputFormat) string {
+	switch format {
+	case playht.Wav:
+		return "audio/wav"
+	case playht.Ogg:
+		return "audio/ogg"
+	case playht.Flac:
+		return "audio/flac"
+	case playht.Mulaw:
+		return "audio/basic"
+	case playht.Raw:
+		return "application/octet-stream"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+// Audio generates deterministic audio in the given format.
+// It generates a 440 Hz tone, except for Mp3 which contains silence.
+// If sampleRate is not positive, DefaultSampleRate is used.
+// Mp3 audio uses the closest MPEG-1 sample rate.
+func Audio(format playht.OutputFormat, sampleRate int32, duration time.Duration) []byte {
+	if sampleRate <= 0 {
+		sampleRate = DefaultSampleRate
+	}
+	switch format {
+	case playht.Wav:
+		return WAV(Tone(sampleRate, duration), sampleRate)
+	case playht.Ogg:
+		return OggFLAC(Tone(sampleRate, duration), sampleRate)
+	case playht.Flac:
+		return FLAC(Tone(sampleRate, duration), sampleRate)
+	case playht.Mulaw:
+		return Mulaw(Tone(sampleRate, duration))
+	case playht.Raw:
+		return PCMFloat32(Tone(sampleRate, duration))
+	default:
+		return MP3Silence(sampleRate, duration)
+	}
+}
+
+// Tone returns 16-bit PCM samples of the 440 Hz tone.
+func Tone(sampleRate int32, duration time.Duration) []int16 {
+	n := int(int64(sampleRate) * int64(duration) / int64(time.Second))
+	samples := make([]int16, n)
+	for i := range samples {
+		v := 0.3 * math.Sin(2*math.Pi*ToneFrequency*float64(i)/float64(sampleRate))
+		samples[i] = int16(v * math.MaxInt16)
+	}
+	return samples
+}
+
+// WAV encodes mono 16-bit PCM samples as WAV.
+func WAV(samples []int16, sampleRate int32) []byte {
+	dataSize := uint32(len(samples) * 2)
+	buf := bytes.NewBuffer(make([]byte, 0, 44+dataSize))
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),             // fmt chunk size
+		uint16(1),              // PCM
+		uint16(1),              // channels
+		uint32(sampleRate),     // sample rate
+		uint32(sampleRate * 2), // byte rate
+		uint16(2),              // block align
+		uint16(16),             // bits per sample
+	} {
+		_ = binary.Write(buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, dataSize)
+	_ = binary.Write(buf, binary.LittleEndian, samples)
+
+	return buf.Bytes()
+}
+
+// Mulaw encodes 16-bit PCM samples as headerless G.711 mu-law.
+func Mulaw(samples []int16) []byte {
+	out := make([]byte, len(samples))
+	for i, s := range samples {
+		out[i] = mulaw(s)
+	}
+	return out
+}
+
+func mulaw(sample int16) byte {
+	const (
+		bias = 0x84
+		clip = 32635
+	)
+	s := int(sample)
+	sign := 0
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > clip {
+		s = clip
+	}
+	s += bias
+
+	exp := 7
+	for mask := 0x4000; s&mask == 0 && exp > 0; mask >>= 1 {
+		exp--
+	}
+	mantissa := (s >> (exp + 3)) & 0x0F
+	return ^byte(sign | exp<<4 | mantissa)
+}
+
+// PCMFloat32 encodes 16-bit PCM samples as headerless little-endian 32-bit float PCM.
+func PCMFloat32(samples []int16) []byte {
+	out := make([]float32, len(samples))
+	for i, s := range samples {
+		out[i] = float32(s) / math.MaxInt16
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(out)*4))
+	_ = binary.Write(buf, binary.LittleEndian, out)
+	return buf.Bytes()
+}
+
+// FLAC encodes mono 16-bit PCM samples as FLAC.
+// The frames use verbatim subframes so the audio is not compressed.
+func FLAC(samples []int16, sampleRate int32) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("fLaC")
+	buf.Write(flacStreamInfo(len(samples), sampleRate, true))
+	for _, frame := range flacFrames(samples) {
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+// flacStreamInfo returns STREAMINFO metadata block.
+func flacStreamInfo(n int, sampleRate int32, last bool) []byte {
+	info := make([]byte, 4+34)
+	if last {
+		info[0] = 0x80
+	}
+	// NOTE: STREAMINFO block type is 0 and its length is 34 bytes
+	info[3] = 34
+
+	binary.BigEndian.PutUint16(info[4:], flacBlockSize)
+	binary.BigEndian.PutUint16(info[6:], flacBlockSize)
+	// min and max frame sizes are unknown so they're left zero;
+	// sample rate (20 bits), channels - 1 (3 bits), bits per sample - 1 (5 bits)
+	// and total samples (36 bits) are packed into the following 8 bytes.
+	packed := uint64(sampleRate)<<44 | uint64(0)<<41 | uint64(15)<<36 | uint64(n)&(1<<36-1)
+	binary.BigEndian.PutUint64(info[14:], packed)
+	// MD5 signature is left zero which means it's unknown
+	return info
+}
+
+// flacFrames returns FLAC frames encoding the samples.
+func flacFrames(samples []int16) [][]byte {
+	var frames [][]byte
+	for num := 0; len(samples) > 0; num++ {
+		size := min(len(samples), flacBlockSize)
+		frames = append(frames, flacFrame(num, samples[:size]))
+		samples = samples[size:]
+	}
+	return frames
+}
+
+// flacFrame returns the FLAC frame with the given number.
+func flacFrame(num int, samples []int16) []byte {
+	frame := &bytes.Buffer{}
+	// sync code and fixed block size strategy
+	frame.Write([]byte{0xFF, 0xF8})
+	// block size is stored as 16 bit value at the end of the header,
+	// sample rate is taken from STREAMINFO
+	frame.WriteByte(0x70)
+	// mono channel and 16 bits per sample
+	frame.WriteByte(0x08)
+	frame.Write(flacUTF8(uint64(num)))
+	_ = binary.Write(frame, binary.BigEndian, uint16(len(samples)-1))
+	frame.WriteByte(crc8(frame.Bytes()))
+
+	// verbatim subframe
+	frame.WriteByte(0x02)
+	_ = binary.Write(frame, binary.BigEndian, samples)
+
+	_ = binary.Write(frame, binary.BigEndian, crc16(frame.Bytes()))
+	return frame.Bytes()
+}
+
+// flacUTF8 encodes v with the extended UTF-8 coding used by FLAC frame headers.
+func flacUTF8(v uint64) []byte {
+	if v < 0x80 {
+		return []byte{byte(v)}
+	}
+	n := 2
+	for v >= 1<<(5*n+1) {
+		n++
+	}
+	out := make([]byte, n)
+	for i := n - 1; i > 0; i-- {
+		out[i] = 0x80 | byte(v&0x3F)
+		v >>= 6
+	}
+	out[0] = byte(0xFF<<(8-n)) | byte(v)
+	return out
+}
+
+// crc8 computes CRC-8 with polynomial x^8 + x^2 + x + 1.
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// crc16 computes CRC-16 with polynomial x^16 + x^15 + x^2 + 1.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x8005
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// OggFLAC encodes mono 16-bit PCM samples as FLAC in Ogg container.
+func OggFLAC(samples []int16, sampleRate int32) []byte {
+	const serial = 0x706c6179
+
+	buf := &bytes.Buffer{}
+
+	// NOTE: the first packet contains the Ogg FLAC mapping header followed
+	// by the FLAC signature and STREAMINFO block. It's followed by a single
+	// header packet with VORBIS_COMMENT block which is required by the mapping.
+	head := &bytes.Buffer{}
+	head.WriteByte(0x7F)
+	head.WriteString("FLAC")
+	head.Write([]byte{1, 0, 0, 1})
+	head.WriteString("fLaC")
+	head.Write(flacStreamInfo(len(samples), sampleRate, false))
+	buf.Write(oggPage(head.Bytes(), 0x02, 0, serial, 0))
+	buf.Write(oggPage(flacVorbisComment(), 0, 0, serial, 1))
+
+	frames := flacFrames(samples)
+	var granule int64
+	for i, frame := range frames {
+		granule = min(granule+flacBlockSize, int64(len(samples)))
+		var flags byte
+		if i == len(frames)-1 {
+			flags = 0x04
+		}
+		buf.Write(oggPage(frame, flags, granule, serial, uint32(i+2)))
+	}
+	if len(frames) == 0 {
+		buf.Write(oggPage(nil, 0x04, 0, serial, 2))
+	}
+
+	return buf.Bytes()
+}
+
+// flacVorbisComment returns the last metadata block with the empty VORBIS_COMMENT.
+func flacVorbisComment() []byte {
+	const vendor = "playhttest"
+
+	comment := make([]byte, 4, 4+4+len(vendor)+4)
+	comment = binary.LittleEndian.AppendUint32(comment, uint32(len(vendor)))
+	comment = append(comment, vendor...)
+	comment = binary.LittleEndian.AppendUint32(comment, 0)
+
+	// NOTE: VORBIS_COMMENT block type is 4
+	size := len(comment) - 4
+	comment[0] = 0x80 | 4
+	comment[1], comment[2], comment[3] = byte(size>>16), byte(size>>8), byte(size)
+	return comment
+}
+
+// oggPage returns the Ogg page containing a single packet.
+func oggPage(packet []byte, flags byte, granule int64, serial, seq uint32) []byte {
+	segments := len(packet)/255 + 1
+	page := make([]byte, 27+segments, 27+segments+len(packet))
+	copy(page, "OggS")
+	page[5] = flags
+	binary.LittleEndian.PutUint64(page[6:], uint64(granule))
+	binary.LittleEndian.PutUint32(page[14:], serial)
+	binary.LittleEndian.PutUint32(page[18:], seq)
+	page[26] = byte(segments)
+	for i := range segments - 1 {
+		page[27+i] = 255
+	}
+	page[27+segments-1] = byte(len(packet) % 255)
+	page = append(page, packet...)
+
+	binary.LittleEndian.PutUint32(page[22:], oggCRC(page))
+	return page
+}
+
+var oggCRCTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		crc := uint32(i) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// oggCRC computes the Ogg page checksum.
+func oggCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc<<8 ^ oggCRCTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// MP3Silence returns MPEG-1 Layer III frames which decode to silence.
+// The closest MPEG-1 sample rate to sampleRate is used.
+func MP3Silence(sampleRate int32, duration time.Duration) []byte {
+	rate, index := mp3SampleRate(sampleRate)
+	samples := int64(rate) * int64(duration) / int64(time.Second)
+	frames := int((samples + mp3FrameSamples - 1) / mp3FrameSamples)
+	size := 144 * mp3Bitrate / rate
+
+	// NOTE: 128 kbps bitrate index, no CRC, no padding, mono.
+	header := []byte{0xFF, 0xFB, 0x90 | index<<2, 0xC0}
+	out := make([]byte, frames*size)
+	for i := range frames {
+		copy(out[i*size:], header)
+	}
+	return out
+}
+
+// mp3SampleRate returns the closest MPEG-1 sample rate and its header index.
+func mp3SampleRate(sampleRate int32) (int, byte) {
+	switch {
+	case sampleRate < 38000:
+		return 32000, 2
+	case sampleRate < 46050:
+		return 44100, 0
+	default:
+		return 48000, 1
+	}
+}
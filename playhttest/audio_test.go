@@ -0,0 +1,97 @@
+package playhttest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksums(t *testing.T) {
+	t.Parallel()
+	check := []byte("123456789")
+	assert.Equal(t, byte(0xF4), crc8(check))
+	assert.Equal(t, uint16(0xFEE8), crc16(check))
+	assert.Equal(t, uint32(0x89A1897F), oggCRC(check))
+}
+
+func TestAudio(t *testing.T) {
+	t.Parallel()
+
+	const sampleRate = 16000
+	duration := 600 * time.Millisecond
+	samples := sampleRate * 6 / 10
+
+	t.Run("wav", func(t *testing.T) {
+		t.Parallel()
+		audio := Audio(playht.Wav, sampleRate, duration)
+		assert.Equal(t, "RIFF", string(audio[:4]))
+		assert.Equal(t, "WAVE", string(audio[8:12]))
+		assert.Equal(t, uint32(sampleRate), binary.LittleEndian.Uint32(audio[24:]))
+		assert.Len(t, audio, 44+samples*2)
+	})
+	t.Run("mulaw", func(t *testing.T) {
+		t.Parallel()
+		audio := Audio(playht.Mulaw, sampleRate, duration)
+		assert.Len(t, audio, samples)
+		assert.Equal(t, byte(0xFF), mulaw(0))
+		assert.Equal(t, byte(0x80), mulaw(32767))
+		assert.Equal(t, byte(0x00), mulaw(-32768))
+	})
+	t.Run("flac", func(t *testing.T) {
+		t.Parallel()
+		audio := Audio(playht.Flac, sampleRate, duration)
+		assert.Equal(t, "fLaC", string(audio[:4]))
+		frames := audio[4+4+34:]
+		// NOTE: CRC-16 of the whole frame including its CRC is zero
+		for remaining := samples; remaining > 0; remaining -= flacBlockSize {
+			size := min(remaining, flacBlockSize)
+			n := 4 + len(flacUTF8(0)) + 2 + 1 + 1 + size*2 + 2
+			assert.Equal(t, []byte{0xFF, 0xF8}, frames[:2])
+			assert.Zero(t, crc16(frames[:n]))
+			frames = frames[n:]
+		}
+		assert.Empty(t, frames)
+	})
+	t.Run("ogg", func(t *testing.T) {
+		t.Parallel()
+		audio := Audio(playht.Ogg, sampleRate, duration)
+		pages := 0
+		for len(audio) > 0 {
+			assert.Equal(t, "OggS", string(audio[:4]))
+			segments := int(audio[26])
+			size := 27 + segments
+			for _, lacing := range audio[27 : 27+segments] {
+				size += int(lacing)
+			}
+			page := bytes.Clone(audio[:size])
+			crc := binary.LittleEndian.Uint32(page[22:])
+			copy(page[22:], []byte{0, 0, 0, 0})
+			assert.Equal(t, crc, oggCRC(page))
+			audio = audio[size:]
+			pages++
+		}
+		// header, vorbis comment and 3 frames
+		assert.Equal(t, 5, pages)
+	})
+	t.Run("mp3", func(t *testing.T) {
+		t.Parallel()
+		audio := Audio(playht.Mp3, 44100, time.Second)
+		assert.Equal(t, []byte{0xFF, 0xFB, 0x90, 0xC0}, audio[:4])
+		assert.Len(t, audio, 39*417)
+	})
+	t.Run("deterministic", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, Audio(playht.Flac, 0, time.Second), Audio(playht.Flac, 0, time.Second))
+	})
+}
+
+func TestFlacUTF8(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []byte{0x7F}, flacUTF8(0x7F))
+	assert.Equal(t, []byte{0xC2, 0x80}, flacUTF8(0x80))
+	assert.Equal(t, []byte{0xE0, 0xA0, 0x80}, flacUTF8(0x800))
+}
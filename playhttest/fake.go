@@ -0,0 +1,692 @@
+package playhttest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	pb "github.com/milosgajdos/go-playht/proto"
+)
+
+// fakeBaseURL is the base URL of the URLs returned by FakeClient.
+const fakeBaseURL = "https://playhttest.invalid"
+
+// Call is the recorded FakeClient method call.
+type Call struct {
+	// Method is the name of the called method.
+	Method string
+	// Args are the call arguments without the context,
+	// the audio writers and the functional options.
+	Args []any
+	// Time is the time of the call.
+	Time time.Time
+}
+
+// FakeClient is an in-memory fake implementing playht.API.
+// It records all the calls and by default generates the same
+// deterministic audio as Server. Jobs are complete once created.
+//
+// Responses are canned by setting the Func field of the method;
+// errors can be scripted via Fail. The Func fields must be set
+// before the client is used. Options which only apply to Server,
+// such as the credentials or the job duration, are ignored.
+type FakeClient struct {
+	GetVoicesFunc                       func(ctx context.Context) ([]playht.Voice, error)
+	GetClonedVoicesFunc                 func(ctx context.Context) ([]playht.ClonedVoice, error)
+	CreateInstantVoiceCloneFromFileFunc func(ctx context.Context, cloneReq *playht.CloneVoiceFileRequest) (*playht.ClonedVoice, error)
+	CreateInstantVoiceCloneFromURLFunc  func(ctx context.Context, cloneReq *playht.CloneVoiceURLRequest) (*playht.ClonedVoice, error)
+	DeleteClonedVoiceFunc               func(ctx context.Context, delReq *playht.DeleteClonedVoiceRequest) (*playht.DeleteClonedVoiceResp, error)
+	CreateTTSJobFunc                    func(ctx context.Context, createReq *playht.CreateTTSJobReq) (*playht.TTSJob, error)
+	GetTTSJobFunc                       func(ctx context.Context, id string) (*playht.TTSJob, error)
+	WaitForTTSJobFunc                   func(ctx context.Context, id string, opts ...playht.WaitOption) (*playht.TTSJob, error)
+	GetTTSJobAudioStreamFunc            func(ctx context.Context, w io.Writer, id string) error
+	CreateTTSJobWithProgressStreamFunc  func(ctx context.Context, w io.Writer, createReq *playht.CreateTTSJobReq) (string, error)
+	GetTTSJobProgressStreamFunc         func(ctx context.Context, w io.Writer, id string) error
+	TTSJobProgressEventsFunc            func(ctx context.Context, id string, opts ...playht.JobProgressOption) iter.Seq2[*playht.JobProgressEvent, error]
+	CreateTTSJobWithProgressEventsFunc  func(ctx context.Context, createReq *playht.CreateTTSJobReq, opts ...playht.JobProgressOption) iter.Seq2[*playht.JobProgressEvent, error]
+	DownloadTTSJobAudioFunc             func(ctx context.Context, job *playht.TTSJob, w io.Writer, opts ...playht.DownloadOption) (int64, error)
+	DownloadTTSJobAudioFileFunc         func(ctx context.Context, job *playht.TTSJob, path string, opts ...playht.DownloadOption) (int64, error)
+	TTSStreamFunc                       func(ctx context.Context, w io.Writer, createReq *playht.CreateTTSStreamReq) error
+	TTSStreamReaderFunc                 func(ctx context.Context, createReq *playht.CreateTTSStreamReq) (io.ReadCloser, error)
+	TTSStreamURLFunc                    func(ctx context.Context, createReq *playht.CreateTTSStreamReq) (*playht.TTSStreamURL, error)
+	TTSGrpcStreamFunc                   func(ctx context.Context, w io.Writer, req *pb.TtsRequest, opts ...playht.GrpcStreamOption) error
+	TTSGrpcStreamReaderFunc             func(ctx context.Context, req *pb.TtsRequest, opts ...playht.GrpcStreamOption) (io.ReadCloser, error)
+	CreateLeaseFunc                     func(ctx context.Context, createReq *playht.CreateLeaseReq) (*playht.Lease, error)
+	RefreshLeaseFunc                    func(ctx context.Context, createReq *playht.CreateLeaseReq) (*playht.Lease, error)
+
+	opts Options
+
+	mu     sync.Mutex
+	seq    int
+	calls  []Call
+	errs   map[string][]error
+	jobs   map[string]*job
+	clones []playht.ClonedVoice
+}
+
+var _ playht.API = (*FakeClient)(nil)
+
+// NewFakeClient creates a new in-memory fake client and returns it.
+func NewFakeClient(opts ...Option) *FakeClient {
+	options := Options{
+		Voices:        DefaultVoices,
+		RuneDuration:  DefaultRuneDuration,
+		LeaseKey:      DefaultLeaseKey,
+		LeaseDuration: DefaultLeaseDuration,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	return &FakeClient{
+		opts: options,
+		errs: make(map[string][]error),
+		jobs: make(map[string]*job),
+	}
+}
+
+// Fail makes the next calls of the method return errs, one error per call.
+// The scripted errors take precedence over the Func fields.
+func (f *FakeClient) Fail(method string, errs ...error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[method] = append(f.errs[method], errs...)
+}
+
+// Calls returns all the recorded calls.
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsTo returns the recorded calls of the given method.
+func (f *FakeClient) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range f.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls and scripted errors.
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.errs = make(map[string][]error)
+}
+
+// ClonedVoices returns the cloned voices.
+func (f *FakeClient) ClonedVoices() []playht.ClonedVoice {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]playht.ClonedVoice(nil), f.clones...)
+}
+
+// Audio returns the audio generated for params.
+func (f *FakeClient) Audio(params *playht.TTSParams) []byte {
+	return paramsAudio(params, f.opts.RuneDuration)
+}
+
+// call records the method call and returns its scripted error, if any.
+// It returns the context error if the context is done.
+func (f *FakeClient) call(ctx context.Context, method string, args ...any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{
+		Method: method,
+		Args:   args,
+		Time:   time.Now(),
+	})
+	if errs := f.errs[method]; len(errs) > 0 {
+		f.errs[method] = errs[1:]
+		return errs[0]
+	}
+	return ctx.Err()
+}
+
+func (f *FakeClient) nextID(prefix string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	return fmt.Sprintf("%s-%d", prefix, f.seq)
+}
+
+// GetVoices implements playht.VoicesAPI.
+func (f *FakeClient) GetVoices(ctx context.Context) ([]playht.Voice, error) {
+	if err := f.call(ctx, "GetVoices"); err != nil {
+		return nil, err
+	}
+	if f.GetVoicesFunc != nil {
+		return f.GetVoicesFunc(ctx)
+	}
+	return append([]playht.Voice(nil), f.opts.Voices...), nil
+}
+
+// GetClonedVoices implements playht.VoicesAPI.
+func (f *FakeClient) GetClonedVoices(ctx context.Context) ([]playht.ClonedVoice, error) {
+	if err := f.call(ctx, "GetClonedVoices"); err != nil {
+		return nil, err
+	}
+	if f.GetClonedVoicesFunc != nil {
+		return f.GetClonedVoicesFunc(ctx)
+	}
+	voices := f.ClonedVoices()
+	if voices == nil {
+		voices = []playht.ClonedVoice{}
+	}
+	return voices, nil
+}
+
+// CreateInstantVoiceCloneFromFile implements playht.ClonesAPI.
+func (f *FakeClient) CreateInstantVoiceCloneFromFile(ctx context.Context, cloneReq *playht.CloneVoiceFileRequest) (*playht.ClonedVoice, error) {
+	if err := f.call(ctx, "CreateInstantVoiceCloneFromFile", cloneReq); err != nil {
+		return nil, err
+	}
+	if f.CreateInstantVoiceCloneFromFileFunc != nil {
+		return f.CreateInstantVoiceCloneFromFileFunc(ctx, cloneReq)
+	}
+	if cloneReq.SampleFile == "" {
+		return nil, badRequest("sample_file is required")
+	}
+	if _, err := os.Stat(cloneReq.SampleFile); err != nil {
+		return nil, err
+	}
+	return f.createClone(cloneReq.VoiceName)
+}
+
+// CreateInstantVoiceCloneFromURL implements playht.ClonesAPI.
+func (f *FakeClient) CreateInstantVoiceCloneFromURL(ctx context.Context, cloneReq *playht.CloneVoiceURLRequest) (*playht.ClonedVoice, error) {
+	if err := f.call(ctx, "CreateInstantVoiceCloneFromURL", cloneReq); err != nil {
+		return nil, err
+	}
+	if f.CreateInstantVoiceCloneFromURLFunc != nil {
+		return f.CreateInstantVoiceCloneFromURLFunc(ctx, cloneReq)
+	}
+	if cloneReq.SampleFileURL == "" {
+		return nil, badRequest("sample_file_url is required")
+	}
+	return f.createClone(cloneReq.VoiceName)
+}
+
+func (f *FakeClient) createClone(name string) (*playht.ClonedVoice, error) {
+	if name == "" {
+		return nil, badRequest("voice_name is required")
+	}
+
+	id := f.nextID("voice")
+	voice := playht.ClonedVoice{
+		ID:   "s3://voice-cloning-zero-shot/playhttest/" + id + "/manifest.json",
+		Name: name,
+		Type: "instant",
+	}
+
+	f.mu.Lock()
+	f.clones = append(f.clones, voice)
+	f.mu.Unlock()
+
+	return &voice, nil
+}
+
+// DeleteClonedVoice implements playht.ClonesAPI.
+func (f *FakeClient) DeleteClonedVoice(ctx context.Context, delReq *playht.DeleteClonedVoiceRequest) (*playht.DeleteClonedVoiceResp, error) {
+	if err := f.call(ctx, "DeleteClonedVoice", delReq); err != nil {
+		return nil, err
+	}
+	if f.DeleteClonedVoiceFunc != nil {
+		return f.DeleteClonedVoiceFunc(ctx, delReq)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, voice := range f.clones {
+		if voice.ID == delReq.VoiceID {
+			f.clones = append(f.clones[:i], f.clones[i+1:]...)
+			return &playht.DeleteClonedVoiceResp{
+				Message: "Voice deleted successfully",
+				Deleted: voice,
+			}, nil
+		}
+	}
+	return nil, notFound("voice not found")
+}
+
+// CreateTTSJob implements playht.JobsAPI.
+func (f *FakeClient) CreateTTSJob(ctx context.Context, createReq *playht.CreateTTSJobReq) (*playht.TTSJob, error) {
+	if err := f.call(ctx, "CreateTTSJob", createReq); err != nil {
+		return nil, err
+	}
+	if f.CreateTTSJobFunc != nil {
+		return f.CreateTTSJobFunc(ctx, createReq)
+	}
+	j, err := f.createJob(createReq)
+	if err != nil {
+		return nil, err
+	}
+	return f.jobStatus(j), nil
+}
+
+func (f *FakeClient) createJob(createReq *playht.CreateTTSJobReq) (*job, error) {
+	params := playht.JobReqParams(createReq)
+	if err := params.Validate(); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	input, err := params.JobReq()
+	if err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	j := &job{audio: f.Audio(params)}
+	j.ID = f.nextID("job")
+	j.Created = time.Now().UTC()
+	j.Input = input
+	j.Status = playht.TTSJobComplete
+	j.Output.Size = len(j.audio)
+	j.Output.Duration = runesDuration(params.Text, f.opts.RuneDuration).Seconds()
+	j.Output.URL = fakeBaseURL + "/audio/" + j.ID
+	j.Links = []playht.Link{{
+		ContentType: "application/json",
+		Description: "Fetches this job's data. Poll it for the latest status.",
+		Href:        fakeBaseURL + "/v2/tts/" + j.ID,
+		Method:      http.MethodGet,
+		Rel:         "self",
+	}}
+
+	f.mu.Lock()
+	f.jobs[j.ID] = j
+	f.mu.Unlock()
+
+	return j, nil
+}
+
+func (f *FakeClient) job(id string) (*job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	j, ok := f.jobs[id]
+	if !ok {
+		return nil, notFound("job not found")
+	}
+	return j, nil
+}
+
+// jobStatus returns a copy of the job status.
+func (f *FakeClient) jobStatus(j *job) *playht.TTSJob {
+	status := j.TTSJob
+	status.Links = append([]playht.Link(nil), j.Links...)
+	return &status
+}
+
+// GetTTSJob implements playht.JobsAPI.
+func (f *FakeClient) GetTTSJob(ctx context.Context, id string) (*playht.TTSJob, error) {
+	if err := f.call(ctx, "GetTTSJob", id); err != nil {
+		return nil, err
+	}
+	if f.GetTTSJobFunc != nil {
+		return f.GetTTSJobFunc(ctx, id)
+	}
+	j, err := f.job(id)
+	if err != nil {
+		return nil, err
+	}
+	return f.jobStatus(j), nil
+}
+
+// WaitForTTSJob implements playht.JobsAPI.
+func (f *FakeClient) WaitForTTSJob(ctx context.Context, id string, opts ...playht.WaitOption) (*playht.TTSJob, error) {
+	if err := f.call(ctx, "WaitForTTSJob", id); err != nil {
+		return nil, err
+	}
+	if f.WaitForTTSJobFunc != nil {
+		return f.WaitForTTSJobFunc(ctx, id, opts...)
+	}
+	j, err := f.job(id)
+	if err != nil {
+		return nil, err
+	}
+	return f.jobStatus(j), nil
+}
+
+// GetTTSJobAudioStream implements playht.JobsAPI.
+func (f *FakeClient) GetTTSJobAudioStream(ctx context.Context, w io.Writer, id string) error {
+	if err := f.call(ctx, "GetTTSJobAudioStream", id); err != nil {
+		return err
+	}
+	if f.GetTTSJobAudioStreamFunc != nil {
+		return f.GetTTSJobAudioStreamFunc(ctx, w, id)
+	}
+	j, err := f.job(id)
+	if err != nil {
+		return err
+	}
+	if j.Input.OutputFormat != "" && j.Input.OutputFormat != playht.Mp3 {
+		return badRequest("audio stream is only available for mp3 jobs")
+	}
+	_, err = w.Write(j.audio)
+	return err
+}
+
+// CreateTTSJobWithProgressStream implements playht.JobsAPI.
+func (f *FakeClient) CreateTTSJobWithProgressStream(ctx context.Context, w io.Writer, createReq *playht.CreateTTSJobReq) (string, error) {
+	if err := f.call(ctx, "CreateTTSJobWithProgressStream", createReq); err != nil {
+		return "", err
+	}
+	if f.CreateTTSJobWithProgressStreamFunc != nil {
+		return f.CreateTTSJobWithProgressStreamFunc(ctx, w, createReq)
+	}
+	j, err := f.createJob(createReq)
+	if err != nil {
+		return "", err
+	}
+	return j.ID, writeProgressEvents(w, j)
+}
+
+// GetTTSJobProgressStream implements playht.JobsAPI.
+func (f *FakeClient) GetTTSJobProgressStream(ctx context.Context, w io.Writer, id string) error {
+	if err := f.call(ctx, "GetTTSJobProgressStream", id); err != nil {
+		return err
+	}
+	if f.GetTTSJobProgressStreamFunc != nil {
+		return f.GetTTSJobProgressStreamFunc(ctx, w, id)
+	}
+	j, err := f.job(id)
+	if err != nil {
+		return err
+	}
+	return writeProgressEvents(w, j)
+}
+
+// writeProgressEvents writes all the job progress events in the SSE format.
+func writeProgressEvents(w io.Writer, j *job) error {
+	buf := &bytes.Buffer{}
+	for id := 1; id <= progressSteps+1; id++ {
+		writeEvent(buf, progressEvent(j, id))
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// TTSJobProgressEvents implements playht.JobsAPI.
+func (f *FakeClient) TTSJobProgressEvents(ctx context.Context, id string, opts ...playht.JobProgressOption) iter.Seq2[*playht.JobProgressEvent, error] {
+	if err := f.call(ctx, "TTSJobProgressEvents", id); err != nil {
+		return failSeq(err)
+	}
+	if f.TTSJobProgressEventsFunc != nil {
+		return f.TTSJobProgressEventsFunc(ctx, id, opts...)
+	}
+	j, err := f.job(id)
+	if err != nil {
+		return failSeq(err)
+	}
+	return progressSeq(ctx, j)
+}
+
+// CreateTTSJobWithProgressEvents implements playht.JobsAPI.
+func (f *FakeClient) CreateTTSJobWithProgressEvents(ctx context.Context, createReq *playht.CreateTTSJobReq, opts ...playht.JobProgressOption) iter.Seq2[*playht.JobProgressEvent, error] {
+	if err := f.call(ctx, "CreateTTSJobWithProgressEvents", createReq); err != nil {
+		return failSeq(err)
+	}
+	if f.CreateTTSJobWithProgressEventsFunc != nil {
+		return f.CreateTTSJobWithProgressEventsFunc(ctx, createReq, opts...)
+	}
+	j, err := f.createJob(createReq)
+	if err != nil {
+		return failSeq(err)
+	}
+	return progressSeq(ctx, j)
+}
+
+// progressSeq returns an iterator over all the job progress events.
+func progressSeq(ctx context.Context, j *job) iter.Seq2[*playht.JobProgressEvent, error] {
+	return func(yield func(*playht.JobProgressEvent, error) bool) {
+		for id := 1; id <= progressSteps+1; id++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(progressEvent(j, id), nil) {
+				return
+			}
+		}
+	}
+}
+
+// failSeq returns an iterator which yields err.
+func failSeq(err error) iter.Seq2[*playht.JobProgressEvent, error] {
+	return func(yield func(*playht.JobProgressEvent, error) bool) {
+		yield(nil, err)
+	}
+}
+
+// DownloadTTSJobAudio implements playht.JobsAPI.
+func (f *FakeClient) DownloadTTSJobAudio(ctx context.Context, job *playht.TTSJob, w io.Writer, opts ...playht.DownloadOption) (int64, error) {
+	if err := f.call(ctx, "DownloadTTSJobAudio", job); err != nil {
+		return 0, err
+	}
+	if f.DownloadTTSJobAudioFunc != nil {
+		return f.DownloadTTSJobAudioFunc(ctx, job, w, opts...)
+	}
+	return f.download(job, w, opts...)
+}
+
+// DownloadTTSJobAudioFile implements playht.JobsAPI.
+func (f *FakeClient) DownloadTTSJobAudioFile(ctx context.Context, job *playht.TTSJob, path string, opts ...playht.DownloadOption) (int64, error) {
+	if err := f.call(ctx, "DownloadTTSJobAudioFile", job, path); err != nil {
+		return 0, err
+	}
+	if f.DownloadTTSJobAudioFileFunc != nil {
+		return f.DownloadTTSJobAudioFileFunc(ctx, job, path, opts...)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	opts = append(opts, playht.WithDownloadOffset(info.Size()))
+	n, err := f.download(job, file, opts...)
+	if err != nil {
+		return n, err
+	}
+	return n, file.Close()
+}
+
+// download writes the job audio starting from the download offset into w.
+func (f *FakeClient) download(job *playht.TTSJob, w io.Writer, opts ...playht.DownloadOption) (int64, error) {
+	options := playht.DownloadOptions{}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	if job == nil || job.Output.URL == "" {
+		return 0, playht.ErrNoJobOutput
+	}
+	j, err := f.job(job.ID)
+	if err != nil {
+		return 0, err
+	}
+	if options.Offset > int64(len(j.audio)) {
+		return 0, fmt.Errorf("%w: offset %d beyond audio size %d", playht.ErrSizeMismatch, options.Offset, len(j.audio))
+	}
+	n, err := w.Write(j.audio[options.Offset:])
+	return int64(n), err
+}
+
+// TTSStream implements playht.StreamsAPI.
+func (f *FakeClient) TTSStream(ctx context.Context, w io.Writer, createReq *playht.CreateTTSStreamReq) error {
+	if err := f.call(ctx, "TTSStream", createReq); err != nil {
+		return err
+	}
+	if f.TTSStreamFunc != nil {
+		return f.TTSStreamFunc(ctx, w, createReq)
+	}
+	audio, err := f.streamAudio(createReq)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(audio)
+	return err
+}
+
+// TTSStreamReader implements playht.StreamsAPI.
+func (f *FakeClient) TTSStreamReader(ctx context.Context, createReq *playht.CreateTTSStreamReq) (io.ReadCloser, error) {
+	if err := f.call(ctx, "TTSStreamReader", createReq); err != nil {
+		return nil, err
+	}
+	if f.TTSStreamReaderFunc != nil {
+		return f.TTSStreamReaderFunc(ctx, createReq)
+	}
+	audio, err := f.streamAudio(createReq)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(audio)), nil
+}
+
+// TTSStreamURL implements playht.StreamsAPI.
+// The returned URL can't be fetched.
+func (f *FakeClient) TTSStreamURL(ctx context.Context, createReq *playht.CreateTTSStreamReq) (*playht.TTSStreamURL, error) {
+	if err := f.call(ctx, "TTSStreamURL", createReq); err != nil {
+		return nil, err
+	}
+	if f.TTSStreamURLFunc != nil {
+		return f.TTSStreamURLFunc(ctx, createReq)
+	}
+	params := playht.StreamReqParams(createReq)
+	if err := params.Validate(); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	return &playht.TTSStreamURL{
+		HRef:   fakeBaseURL + "/v2/tts/stream/" + f.nextID("stream"),
+		Method: http.MethodGet,
+		CType:  ContentType(params.OutputFormat),
+		Rel:    "stream",
+		Desc:   "Stream the audio bytes.",
+	}, nil
+}
+
+func (f *FakeClient) streamAudio(createReq *playht.CreateTTSStreamReq) ([]byte, error) {
+	params := playht.StreamReqParams(createReq)
+	if err := params.Validate(); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	return f.Audio(params), nil
+}
+
+// TTSGrpcStream implements playht.StreamsAPI.
+func (f *FakeClient) TTSGrpcStream(ctx context.Context, w io.Writer, req *pb.TtsRequest, opts ...playht.GrpcStreamOption) error {
+	if err := f.call(ctx, "TTSGrpcStream", req); err != nil {
+		return err
+	}
+	if f.TTSGrpcStreamFunc != nil {
+		return f.TTSGrpcStreamFunc(ctx, w, req, opts...)
+	}
+	audio, err := f.grpcAudio(req)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(audio)
+	return err
+}
+
+// TTSGrpcStreamReader implements playht.StreamsAPI.
+func (f *FakeClient) TTSGrpcStreamReader(ctx context.Context, req *pb.TtsRequest, opts ...playht.GrpcStreamOption) (io.ReadCloser, error) {
+	if err := f.call(ctx, "TTSGrpcStreamReader", req); err != nil {
+		return nil, err
+	}
+	if f.TTSGrpcStreamReaderFunc != nil {
+		return f.TTSGrpcStreamReaderFunc(ctx, req, opts...)
+	}
+	audio, err := f.grpcAudio(req)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(audio)), nil
+}
+
+func (f *FakeClient) grpcAudio(req *pb.TtsRequest) ([]byte, error) {
+	params, err := playht.FromPbParams(req.GetParams())
+	if err != nil {
+		return nil, err
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	// NOTE: raw PCM is the gRPC API default format
+	if params.OutputFormat == "" {
+		params.OutputFormat = playht.Raw
+	}
+	return f.Audio(params), nil
+}
+
+// CreateLease implements playht.LeasesAPI.
+func (f *FakeClient) CreateLease(ctx context.Context, createReq *playht.CreateLeaseReq) (*playht.Lease, error) {
+	if err := f.call(ctx, "CreateLease", createReq); err != nil {
+		return nil, err
+	}
+	if f.CreateLeaseFunc != nil {
+		return f.CreateLeaseFunc(ctx, createReq)
+	}
+	return f.lease()
+}
+
+// RefreshLease implements playht.LeasesAPI.
+func (f *FakeClient) RefreshLease(ctx context.Context, createReq *playht.CreateLeaseReq) (*playht.Lease, error) {
+	if err := f.call(ctx, "RefreshLease", createReq); err != nil {
+		return nil, err
+	}
+	if f.RefreshLeaseFunc != nil {
+		return f.RefreshLeaseFunc(ctx, createReq)
+	}
+	return f.lease()
+}
+
+func (f *FakeClient) lease() (*playht.Lease, error) {
+	md := f.opts.LeaseMetadata
+	if md.UserID == "" {
+		md.UserID = f.opts.UserID
+	}
+	data, err := NewLease(f.opts.LeaseKey, time.Now(), f.opts.LeaseDuration, md)
+	if err != nil {
+		return nil, err
+	}
+	return playht.ParseLease(data)
+}
+
+// Close implements playht.API.
+func (f *FakeClient) Close() error {
+	return f.call(context.Background(), "Close")
+}
+
+// badRequest returns the API error with 400 status code.
+func badRequest(msg string) error {
+	return apiError(http.StatusBadRequest, "INVALID_REQUEST", msg)
+}
+
+// notFound returns the API error with 404 status code.
+func notFound(msg string) error {
+	return apiError(http.StatusNotFound, "NOT_FOUND", msg)
+}
+
+func apiError(status int, id, msg string) error {
+	return &playht.APIError{
+		Generic:    &playht.ErrGeneric{ID: id, Message: msg},
+		StatusCode: status,
+	}
+}
@@ -0,0 +1,207 @@
+package playhttest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/milosgajdos/go-playht/playhttest"
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClientStreams(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	defer srv.Close()
+
+	fake := playhttest.NewFakeClient()
+	ctx := context.Background()
+	req := &playht.CreateTTSStreamReq{Text: "hello", Voice: "voice", OutputFormat: playht.Wav}
+
+	// the fake generates the same audio as the server
+	for _, api := range []playht.API{newClient(srv), fake} {
+		buf := &bytes.Buffer{}
+		assert.NoError(t, api.TTSStream(ctx, buf, req))
+		assert.Equal(t, srv.Audio(playht.StreamReqParams(req)), buf.Bytes())
+		assert.NoError(t, api.Close())
+	}
+
+	r, err := fake.TTSStreamReader(ctx, req)
+	assert.NoError(t, err)
+	audio, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, fake.Audio(playht.StreamReqParams(req)), audio)
+
+	grpcReq := playht.MakeGrpcStreamRequest(nil, req)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, fake.TTSGrpcStream(ctx, buf, grpcReq))
+	assert.Equal(t, audio, buf.Bytes())
+
+	// gRPC streams default to raw PCM like the gRPC server
+	rawReq := &playht.CreateTTSStreamReq{Text: "hello", Voice: "voice"}
+	buf.Reset()
+	assert.NoError(t, fake.TTSGrpcStream(ctx, buf, playht.MakeGrpcStreamRequest(nil, rawReq)))
+	rawParams := playht.StreamReqParams(rawReq)
+	rawParams.OutputFormat = playht.Raw
+	assert.Equal(t, fake.Audio(rawParams), buf.Bytes())
+
+	streamURL, err := fake.TTSStreamURL(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, playhttest.ContentType(playht.Wav), streamURL.CType)
+
+	// invalid requests fail the same way as with the server
+	err = fake.TTSStream(ctx, io.Discard, &playht.CreateTTSStreamReq{Voice: "voice"})
+	var apiErr *playht.APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	}
+
+	calls := fake.Calls()
+	assert.Len(t, calls, 7)
+	assert.Equal(t, "TTSStream", calls[0].Method)
+	assert.Equal(t, []any{req}, calls[0].Args)
+}
+
+func TestFakeClientJobs(t *testing.T) {
+	t.Parallel()
+
+	fake := playhttest.NewFakeClient()
+	ctx := context.Background()
+	req := &playht.CreateTTSJobReq{Text: "hello", Voice: "voice", OutputFormat: playht.Mp3}
+
+	job, err := fake.CreateTTSJob(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, playht.TTSJobComplete, job.Status)
+
+	job, err = fake.WaitForTTSJob(ctx, job.ID)
+	assert.NoError(t, err)
+
+	audio := &bytes.Buffer{}
+	n, err := fake.DownloadTTSJobAudio(ctx, job, audio)
+	assert.NoError(t, err)
+	assert.EqualValues(t, job.Output.Size, n)
+	assert.Equal(t, fake.Audio(playht.JobReqParams(req)), audio.Bytes())
+
+	path := filepath.Join(t.TempDir(), "audio.mp3")
+	assert.NoError(t, os.WriteFile(path, audio.Bytes()[:10], 0o644))
+	n, err = fake.DownloadTTSJobAudioFile(ctx, job, path)
+	assert.NoError(t, err)
+	assert.EqualValues(t, job.Output.Size-10, n)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, audio.Bytes(), data)
+
+	var events []*playht.JobProgressEvent
+	for event, err := range fake.TTSJobProgressEvents(ctx, job.ID) {
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+	if assert.NotEmpty(t, events) {
+		last := events[len(events)-1]
+		assert.True(t, last.IsTerminal())
+		assert.Equal(t, job.Output.URL, last.URL)
+	}
+
+	stream := &bytes.Buffer{}
+	id, err := fake.CreateTTSJobWithProgressStream(ctx, stream, req)
+	assert.NoError(t, err)
+	assert.Contains(t, stream.String(), "event: completed")
+	assert.Contains(t, stream.String(), id)
+
+	_, err = fake.GetTTSJob(ctx, "missing")
+	assert.ErrorIs(t, err, playht.ErrNotFound)
+}
+
+func TestFakeClientVoices(t *testing.T) {
+	t.Parallel()
+
+	fake := playhttest.NewFakeClient()
+	ctx := context.Background()
+
+	voices, err := fake.GetVoices(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, playhttest.DefaultVoices, voices)
+
+	voice, err := fake.CreateInstantVoiceCloneFromURL(ctx, &playht.CloneVoiceURLRequest{
+		SampleFileURL: "https://example.com/sample.wav",
+		VoiceName:     "clone",
+	})
+	assert.NoError(t, err)
+
+	cloned, err := fake.GetClonedVoices(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []playht.ClonedVoice{*voice}, cloned)
+
+	resp, err := fake.DeleteClonedVoice(ctx, &playht.DeleteClonedVoiceRequest{VoiceID: voice.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, *voice, resp.Deleted)
+	assert.Empty(t, fake.ClonedVoices())
+
+	_, err = fake.DeleteClonedVoice(ctx, &playht.DeleteClonedVoiceRequest{VoiceID: voice.ID})
+	assert.ErrorIs(t, err, playht.ErrNotFound)
+}
+
+func TestFakeClientLease(t *testing.T) {
+	t.Parallel()
+
+	md := playht.LeaseMetadata{UserID: "user", InferenceAddress: "localhost:8081"}
+	fake := playhttest.NewFakeClient(playhttest.WithLeaseMetadata(md))
+
+	lease, err := fake.CreateLease(context.Background(), &playht.CreateLeaseReq{})
+	assert.NoError(t, err)
+	assert.Equal(t, md.UserID, lease.Metadata.UserID)
+	assert.Equal(t, md.InferenceAddress, lease.Metadata.InferenceAddress)
+
+	data, err := lease.MarshalBinary()
+	assert.NoError(t, err)
+	_, err = playhttest.VerifyLease(playhttest.DefaultLeaseKey, data, lease.Created)
+	assert.NoError(t, err)
+}
+
+func TestFakeClientCanned(t *testing.T) {
+	t.Parallel()
+
+	fake := playhttest.NewFakeClient()
+	fake.GetVoicesFunc = func(context.Context) ([]playht.Voice, error) {
+		return []playht.Voice{{ID: "canned"}}, nil
+	}
+	fake.TTSGrpcStreamFunc = func(_ context.Context, w io.Writer, _ *pb.TtsRequest, _ ...playht.GrpcStreamOption) error {
+		_, err := io.WriteString(w, "canned")
+		return err
+	}
+	errBoom := errors.New("boom")
+	fake.Fail("GetVoices", errBoom, playht.ErrRateLimited)
+
+	ctx := context.Background()
+
+	_, err := fake.GetVoices(ctx)
+	assert.ErrorIs(t, err, errBoom)
+	_, err = fake.GetVoices(ctx)
+	assert.ErrorIs(t, err, playht.ErrRateLimited)
+	voices, err := fake.GetVoices(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []playht.Voice{{ID: "canned"}}, voices)
+
+	buf := &strings.Builder{}
+	assert.NoError(t, fake.TTSGrpcStream(ctx, buf, &pb.TtsRequest{}))
+	assert.Equal(t, "canned", buf.String())
+
+	assert.Len(t, fake.CallsTo("GetVoices"), 3)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = fake.GetClonedVoices(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+}
@@ -0,0 +1,312 @@
+package playhttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	pb "github.com/milosgajdos/go-playht/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// bufconnSize is the size of the bufconn listener buffer.
+const bufconnSize = 1 << 20
+
+// GrpcOptions configure the fake gRPC server.
+type GrpcOptions struct {
+	// LeaseKey is the key used to verify the lease signatures.
+	LeaseKey []byte
+	// SkipLeaseCheck disables the lease verification.
+	SkipLeaseCheck bool
+	// RuneDuration is the audio duration generated per text rune.
+	RuneDuration time.Duration
+	// ChunkSize is the size of the streamed audio chunks.
+	ChunkSize int
+	// Latency is the delay before sending each response.
+	Latency time.Duration
+	// Responses returns the responses streamed for the request.
+	// If nil, the responses stream the generated audio.
+	Responses func(*pb.TtsRequest) []*pb.TtsResponse
+	// FailAfter is the number of audio chunks sent before
+	// the stream fails with FailErr. It's only used if FailErr is set.
+	FailAfter int
+	// FailErr is the error the stream fails with.
+	FailErr error
+	// Status is the status sent at the end of the stream.
+	Status *pb.Status
+}
+
+// GrpcOption is a fake gRPC server functional option.
+type GrpcOption func(*GrpcOptions)
+
+// WithGrpcLeaseKey sets the key used to verify the lease signatures.
+func WithGrpcLeaseKey(key []byte) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.LeaseKey = key
+	}
+}
+
+// WithoutLeaseCheck disables the lease verification.
+func WithoutLeaseCheck() GrpcOption {
+	return func(o *GrpcOptions) {
+		o.SkipLeaseCheck = true
+	}
+}
+
+// WithGrpcRuneDuration sets the audio duration generated per text rune.
+func WithGrpcRuneDuration(d time.Duration) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.RuneDuration = d
+	}
+}
+
+// WithGrpcChunkSize sets the size of the streamed audio chunks.
+func WithGrpcChunkSize(size int) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.ChunkSize = size
+	}
+}
+
+// WithGrpcLatency sets the delay before sending each response.
+func WithGrpcLatency(d time.Duration) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.Latency = d
+	}
+}
+
+// WithGrpcResponses sets the func which returns the responses streamed for the request.
+func WithGrpcResponses(fn func(*pb.TtsRequest) []*pb.TtsResponse) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.Responses = fn
+	}
+}
+
+// WithGrpcFailure makes the stream fail with err after sending n audio chunks.
+// Use status.Error to create err with the given gRPC status code.
+func WithGrpcFailure(n int, err error) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.FailAfter = n
+		o.FailErr = err
+	}
+}
+
+// WithGrpcStatus sets the status sent at the end of the stream.
+func WithGrpcStatus(code pb.Code, messages ...string) GrpcOption {
+	return func(o *GrpcOptions) {
+		o.Status = &pb.Status{Code: code, Message: messages}
+	}
+}
+
+// GrpcServer is the fake PlayHT gRPC Tts service.
+type GrpcServer struct {
+	pb.UnimplementedTtsServer
+	opts GrpcOptions
+
+	mu       sync.Mutex
+	seq      int
+	requests []*pb.TtsRequest
+}
+
+// NewGrpcServer creates a new fake gRPC Tts service and returns it.
+// Register it with grpc.Server via pb.RegisterTtsServer or use NewBufconnServer.
+func NewGrpcServer(opts ...GrpcOption) *GrpcServer {
+	options := GrpcOptions{
+		LeaseKey:     DefaultLeaseKey,
+		RuneDuration: DefaultRuneDuration,
+		ChunkSize:    DefaultChunkSize,
+		Status:       &pb.Status{Code: pb.Code_CODE_COMPLETE},
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	return &GrpcServer{
+		opts: options,
+	}
+}
+
+// Requests returns all the received requests.
+func (s *GrpcServer) Requests() []*pb.TtsRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reqs := make([]*pb.TtsRequest, 0, len(s.requests))
+	for _, req := range s.requests {
+		reqs = append(reqs, proto.Clone(req).(*pb.TtsRequest))
+	}
+	return reqs
+}
+
+// Tts implements pb.TtsServer.
+func (s *GrpcServer) Tts(req *pb.TtsRequest, stream pb.Tts_TtsServer) error {
+	s.mu.Lock()
+	s.seq++
+	id := fmt.Sprintf("stream-%d", s.seq)
+	s.requests = append(s.requests, proto.Clone(req).(*pb.TtsRequest))
+	s.mu.Unlock()
+
+	if !s.opts.SkipLeaseCheck {
+		if _, err := VerifyLease(s.opts.LeaseKey, req.GetLease(), time.Now()); err != nil {
+			return leaseError(err)
+		}
+	}
+
+	resps, err := s.responses(id, req)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	chunks := 0
+	for _, resp := range resps {
+		if s.opts.FailErr != nil && chunks >= s.opts.FailAfter {
+			return s.opts.FailErr
+		}
+		if err := sleep(ctx, s.opts.Latency); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		if len(resp.Data) > 0 {
+			chunks++
+		}
+	}
+	if s.opts.FailErr != nil {
+		return s.opts.FailErr
+	}
+	return nil
+}
+
+// responses returns the responses streamed for req.
+func (s *GrpcServer) responses(id string, req *pb.TtsRequest) ([]*pb.TtsResponse, error) {
+	if s.opts.Responses != nil {
+		return s.opts.Responses(req), nil
+	}
+
+	params, err := playht.FromPbParams(req.GetParams())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// NOTE: raw PCM is the gRPC API default format
+	if params.OutputFormat == "" {
+		params.OutputFormat = playht.Raw
+	}
+	var sampleRate int32
+	if params.SampleRate != nil {
+		sampleRate = *params.SampleRate
+	}
+	audio := Audio(params.OutputFormat, sampleRate, runesDuration(params.Text, s.opts.RuneDuration))
+
+	resps := []*pb.TtsResponse{{
+		Id:     id,
+		Status: &pb.Status{Code: pb.Code_CODE_IN_PROGRESS},
+	}}
+	size := max(s.opts.ChunkSize, 1)
+	for seq := int32(1); len(audio) > 0; seq++ {
+		n := min(size, len(audio))
+		resps = append(resps, &pb.TtsResponse{
+			Sequence: seq,
+			Id:       id,
+			Data:     audio[:n],
+		})
+		audio = audio[n:]
+	}
+	if s.opts.Status != nil {
+		resps = append(resps, &pb.TtsResponse{
+			Id:     id,
+			Status: s.opts.Status,
+		})
+	}
+	return resps, nil
+}
+
+// leaseError returns the gRPC error for the lease verification error.
+func leaseError(err error) error {
+	switch {
+	case errors.Is(err, ErrLeaseExpired):
+		return status.Error(codes.Unauthenticated, "lease expired")
+	case errors.Is(err, ErrInvalidSignature):
+		return status.Error(codes.Unauthenticated, "invalid lease signature")
+	default:
+		return status.Errorf(codes.Unauthenticated, "invalid lease: %v", err)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// BufconnServer serves the fake gRPC Tts service over in-memory connection.
+// Plug it into the client via playht.WithGRPCClient(server.Conn()).
+type BufconnServer struct {
+	*GrpcServer
+	server *grpc.Server
+	lis    *bufconn.Listener
+	conn   *grpc.ClientConn
+}
+
+// NewBufconnServer starts a new fake gRPC Tts server over bufconn and returns it.
+// The server must be closed when no longer needed.
+func NewBufconnServer(opts ...GrpcOption) (*BufconnServer, error) {
+	lis := bufconn.Listen(bufconnSize)
+	tts := NewGrpcServer(opts...)
+
+	server := grpc.NewServer()
+	pb.RegisterTtsServer(server, tts)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+
+	return &BufconnServer{
+		GrpcServer: tts,
+		server:     server,
+		lis:        lis,
+		conn:       conn,
+	}, nil
+}
+
+// Conn returns the client connection to the server.
+func (s *BufconnServer) Conn() *grpc.ClientConn {
+	return s.conn
+}
+
+// Close closes the client connection and stops the server.
+func (s *BufconnServer) Close() error {
+	err := s.conn.Close()
+	s.server.Stop()
+	return err
+}
@@ -0,0 +1,159 @@
+package playhttest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/milosgajdos/go-playht/playhttest"
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newGrpcClient(t *testing.T, httpOpts []playhttest.Option, grpcOpts ...playhttest.GrpcOption) (*playht.Client, *playhttest.Server, *playhttest.BufconnServer) {
+	t.Helper()
+
+	srv := playhttest.NewServer(httpOpts...)
+	t.Cleanup(srv.Close)
+
+	grpcSrv, err := playhttest.NewBufconnServer(grpcOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { grpcSrv.Close() })
+
+	c := newClient(srv, playht.WithGRPCClient(grpcSrv.Conn()))
+	t.Cleanup(func() { c.Close() })
+
+	return c, srv, grpcSrv
+}
+
+func TestGrpcServer(t *testing.T) {
+	t.Parallel()
+
+	req := &playht.CreateTTSStreamReq{
+		Text:         "hello",
+		Voice:        "voice",
+		OutputFormat: playht.Wav,
+		VoiceEngine:  playht.PlayHTv2,
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+		c, _, grpcSrv := newGrpcClient(t, nil, playhttest.WithGrpcChunkSize(1000))
+
+		var statuses []pb.Code
+		buf := &bytes.Buffer{}
+		err := c.TTSGrpcStream(context.Background(), buf, playht.MakeGrpcStreamRequest(nil, req),
+			playht.WithSequenceCheck(),
+			playht.WithStatusFunc(func(_ int32, status *pb.Status) {
+				statuses = append(statuses, status.Code)
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, playhttest.NewHandler().Audio(playht.StreamReqParams(req)), buf.Bytes())
+		assert.Equal(t, []pb.Code{pb.Code_CODE_IN_PROGRESS, pb.Code_CODE_COMPLETE}, statuses)
+
+		reqs := grpcSrv.Requests()
+		if assert.Len(t, reqs, 1) {
+			assert.NotEmpty(t, reqs[0].Lease)
+			assert.JSONEq(t, `{"voice_engine":"PlayHT2.0"}`, reqs[0].Params.GetOther())
+		}
+	})
+	t.Run("default raw format", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil)
+
+		rawReq := &playht.CreateTTSStreamReq{Text: "hello", Voice: "voice"}
+		buf := &bytes.Buffer{}
+		assert.NoError(t, c.TTSGrpcStream(context.Background(), buf, playht.MakeGrpcStreamRequest(nil, rawReq)))
+
+		params := playht.StreamReqParams(rawReq)
+		params.OutputFormat = playht.Raw
+		assert.Equal(t, playhttest.NewHandler().Audio(params), buf.Bytes())
+
+		// the audio is 32-bit float PCM of the default sample rate
+		samples := make([]float32, buf.Len()/4)
+		assert.NoError(t, binary.Read(bytes.NewReader(buf.Bytes()), binary.LittleEndian, samples))
+		assert.Len(t, samples, len(playhttest.Tone(playhttest.DefaultSampleRate, playhttest.DefaultRuneDuration*5)))
+		for _, sample := range samples {
+			assert.True(t, sample >= -1 && sample <= 1)
+		}
+	})
+	t.Run("expired lease", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil)
+
+		lease, err := playhttest.NewLease(playhttest.DefaultLeaseKey, time.Now().Add(-2*time.Hour), time.Hour, playht.LeaseMetadata{})
+		assert.NoError(t, err)
+		err = c.TTSGrpcStream(context.Background(), &bytes.Buffer{}, playht.MakeGrpcStreamRequest(lease, req))
+		assert.ErrorIs(t, err, playht.ErrLeaseExpired)
+	})
+	t.Run("managed lease refresh", func(t *testing.T) {
+		t.Parallel()
+		c, srv, grpcSrv := newGrpcClient(t, []playhttest.Option{playhttest.WithLeaseKey([]byte("other"))})
+
+		err := c.TTSGrpcStream(context.Background(), &bytes.Buffer{}, playht.MakeGrpcStreamRequest(nil, req))
+		assert.True(t, playht.IsAuthError(err))
+		// NOTE: the stream is reopened once with a refreshed lease
+		assert.Len(t, grpcSrv.Requests(), 2)
+		assert.Len(t, srv.RequestsTo("POST", "/v2/leases"), 2)
+	})
+	t.Run("mid-stream failure", func(t *testing.T) {
+		t.Parallel()
+		streamErr := status.Error(codes.Unavailable, "unavailable")
+		c, _, _ := newGrpcClient(t, nil, playhttest.WithGrpcChunkSize(10), playhttest.WithGrpcFailure(2, streamErr))
+
+		buf := &bytes.Buffer{}
+		err := c.TTSGrpcStream(context.Background(), buf, playht.MakeGrpcStreamRequest(nil, req))
+		assert.True(t, playht.IsRetryable(err))
+		assert.Equal(t, 20, buf.Len())
+	})
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil, playhttest.WithGrpcStatus(pb.Code_CODE_ERROR, "boom"))
+
+		err := c.TTSGrpcStream(context.Background(), &bytes.Buffer{}, playht.MakeGrpcStreamRequest(nil, req))
+		var statusErr *playht.StreamStatusError
+		if assert.ErrorAs(t, err, &statusErr) {
+			assert.Equal(t, []string{"boom"}, statusErr.Messages)
+		}
+	})
+	t.Run("scripted responses", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil, playhttest.WithGrpcResponses(func(*pb.TtsRequest) []*pb.TtsResponse {
+			return []*pb.TtsResponse{
+				{Sequence: 1, Data: []byte("foo")},
+				{Sequence: 3, Data: []byte("baz")},
+				{Sequence: 2, Data: []byte("bar")},
+				{Status: &pb.Status{Code: pb.Code_CODE_COMPLETE}},
+			}
+		}))
+
+		buf := &bytes.Buffer{}
+		err := c.TTSGrpcStream(context.Background(), buf, playht.MakeGrpcStreamRequest(nil, req), playht.WithReorder(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "foobarbaz", buf.String())
+	})
+	t.Run("latency", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil, playhttest.WithGrpcLatency(time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := c.TTSGrpcStream(ctx, &bytes.Buffer{}, playht.MakeGrpcStreamRequest(nil, req))
+		assert.True(t, playht.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded), err)
+	})
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+		c, _, _ := newGrpcClient(t, nil)
+
+		err := c.TTSGrpcStream(context.Background(), &bytes.Buffer{}, playht.MakeGrpcStreamRequest(nil, &playht.CreateTTSStreamReq{Text: "hello"}))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
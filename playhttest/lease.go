@@ -0,0 +1,60 @@
+package playhttest
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+)
+
+// DefaultLeaseDuration is the default duration of the issued leases.
+const DefaultLeaseDuration = time.Hour
+
+var (
+	// DefaultLeaseKey is the default key used to sign the leases.
+	DefaultLeaseKey = []byte("playhttest")
+	// ErrInvalidSignature is returned when the lease signature is not valid.
+	ErrInvalidSignature = errors.New("invalid lease signature")
+	// ErrLeaseExpired is returned when the lease has expired.
+	ErrLeaseExpired = errors.New("lease expired")
+)
+
+// NewLease returns a new lease in the PlayHT binary lease layout.
+// The lease is signed with HMAC-SHA512 of the lease payload using key.
+func NewLease(key []byte, created time.Time, duration time.Duration, md playht.LeaseMetadata) ([]byte, error) {
+	lease := &playht.Lease{
+		Signature: make([]byte, playht.LeaseSignatureSize),
+		Created:   created,
+		Duration:  duration,
+		Metadata:  md,
+	}
+	data, err := lease.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data, sign(key, data[playht.LeaseSignatureSize:]))
+	return data, nil
+}
+
+// VerifyLease verifies the lease signature and expiration and returns the parsed lease.
+func VerifyLease(key []byte, data []byte, now time.Time) (*playht.Lease, error) {
+	lease, err := playht.ParseLease(data)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(lease.Signature, sign(key, data[playht.LeaseSignatureSize:])) {
+		return nil, ErrInvalidSignature
+	}
+	if !lease.Valid(now) {
+		return nil, ErrLeaseExpired
+	}
+	return lease, nil
+}
+
+func sign(key, payload []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,763 @@
+// Package playhttest provides a fake PlayHT API server for tests.
+package playhttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/milosgajdos/go-playht"
+)
+
+const (
+	// DefaultRuneDuration is the default audio duration generated per text rune.
+	DefaultRuneDuration = 50 * time.Millisecond
+	// DefaultChunkSize is the default size of the streamed audio chunks.
+	DefaultChunkSize = 4096
+	// progressSteps is the number of job progress events sent before completion.
+	progressSteps = 4
+)
+
+// DefaultVoices are the stock voices returned by the server by default.
+var DefaultVoices = []playht.Voice{
+	{
+		ID:       "s3://voice-cloning-zero-shot/test/adolfo/manifest.json",
+		Name:     "Adolfo",
+		Accent:   "american",
+		Age:      "adult",
+		Gender:   "male",
+		Language: "English (US)",
+		LangCode: "en-US",
+		Style:    "narrative",
+	},
+	{
+		ID:       "s3://voice-cloning-zero-shot/test/jennifer/manifest.json",
+		Name:     "Jennifer",
+		Accent:   "american",
+		Age:      "adult",
+		Gender:   "female",
+		Language: "English (US)",
+		LangCode: "en-US",
+		Style:    "videos",
+	},
+}
+
+// Options configure the fake server.
+type Options struct {
+	// SecretKey is the API secret key required by the server.
+	// If empty, the Authorization header is not checked.
+	SecretKey string
+	// UserID is the user ID required by the server.
+	// If empty, the X-USER-ID header is not checked.
+	UserID string
+	// Voices are the stock voices.
+	Voices []playht.Voice
+	// RuneDuration is the audio duration generated per text rune.
+	RuneDuration time.Duration
+	// ChunkSize is the size of the streamed audio chunks.
+	ChunkSize int
+	// JobDuration is the time it takes the TTS jobs to complete.
+	JobDuration time.Duration
+	// LeaseKey is the key used to sign the leases.
+	LeaseKey []byte
+	// LeaseDuration is the duration of the issued leases.
+	LeaseDuration time.Duration
+	// LeaseMetadata is the metadata of the issued leases.
+	LeaseMetadata playht.LeaseMetadata
+}
+
+// Option is a fake server functional option.
+type Option func(*Options)
+
+// WithCredentials sets the credentials required by the server.
+func WithCredentials(secretKey, userID string) Option {
+	return func(o *Options) {
+		o.SecretKey = secretKey
+		o.UserID = userID
+	}
+}
+
+// WithVoices sets the stock voices.
+func WithVoices(voices ...playht.Voice) Option {
+	return func(o *Options) {
+		o.Voices = voices
+	}
+}
+
+// WithRuneDuration sets the audio duration generated per text rune.
+func WithRuneDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.RuneDuration = d
+	}
+}
+
+// WithChunkSize sets the size of the streamed audio chunks.
+func WithChunkSize(size int) Option {
+	return func(o *Options) {
+		o.ChunkSize = size
+	}
+}
+
+// WithJobDuration sets the time it takes the TTS jobs to complete.
+func WithJobDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.JobDuration = d
+	}
+}
+
+// WithLeaseKey sets the key used to sign the leases.
+func WithLeaseKey(key []byte) Option {
+	return func(o *Options) {
+		o.LeaseKey = key
+	}
+}
+
+// WithLeaseDuration sets the duration of the issued leases.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.LeaseDuration = d
+	}
+}
+
+// WithLeaseMetadata sets the metadata of the issued leases.
+func WithLeaseMetadata(md playht.LeaseMetadata) Option {
+	return func(o *Options) {
+		o.LeaseMetadata = md
+	}
+}
+
+// Failure is a scripted request failure.
+type Failure struct {
+	// Method is the HTTP method of the failing requests.
+	// If empty, requests with any method fail.
+	Method string
+	// Path is the URL path of the failing requests.
+	// If empty, requests with any path fail.
+	Path string
+	// Times is the number of the failing requests.
+	// Zero means a single request; negative means all requests.
+	Times int
+	// Status is the response status code.
+	Status int
+	// Header contains the response headers.
+	Header http.Header
+	// ContentType is the response content type.
+	// It defaults to application/json.
+	ContentType string
+	// Body is the response body.
+	Body string
+}
+
+// RateLimit returns a failure which responds to requests to path with 429 status.
+func RateLimit(path string) Failure {
+	return Failure{
+		Path:   path,
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": []string{"0"}},
+		Body:   `{"error_message":"Rate limit exceeded","error_id":"RATE_LIMIT_EXCEEDED"}`,
+	}
+}
+
+// InternalError returns a failure which responds to requests to path with 500 status.
+func InternalError(path string) Failure {
+	return Failure{
+		Path:   path,
+		Status: http.StatusInternalServerError,
+		Body:   `{"message":"internal server error","error":"playhttest"}`,
+	}
+}
+
+// MalformedJSON returns a failure which responds to requests to path with malformed JSON.
+func MalformedJSON(path string) Failure {
+	return Failure{
+		Path:   path,
+		Status: http.StatusOK,
+		Body:   `{"id":`,
+	}
+}
+
+func (f *Failure) matches(r *http.Request) bool {
+	return (f.Method == "" || f.Method == r.Method) &&
+		(f.Path == "" || f.Path == r.URL.Path)
+}
+
+// Request is the recorded request.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+}
+
+// job is the TTS job state.
+type job struct {
+	playht.TTSJob
+	audio []byte
+}
+
+// Handler is the fake PlayHT API HTTP handler.
+// Its state is kept in memory.
+type Handler struct {
+	opts Options
+	mux  *http.ServeMux
+
+	mu       sync.Mutex
+	seq      int
+	requests []Request
+	failures []*Failure
+	jobs     map[string]*job
+	streams  map[string]*playht.TTSParams
+	clones   []playht.ClonedVoice
+}
+
+// NewHandler creates a new fake PlayHT API handler and returns it.
+func NewHandler(opts ...Option) *Handler {
+	options := Options{
+		Voices:        DefaultVoices,
+		RuneDuration:  DefaultRuneDuration,
+		ChunkSize:     DefaultChunkSize,
+		LeaseKey:      DefaultLeaseKey,
+		LeaseDuration: DefaultLeaseDuration,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	h := &Handler{
+		opts:    options,
+		mux:     http.NewServeMux(),
+		jobs:    make(map[string]*job),
+		streams: make(map[string]*playht.TTSParams),
+	}
+
+	h.mux.HandleFunc("POST /v2/tts", h.createJob)
+	h.mux.HandleFunc("GET /v2/tts/{id}", h.getJob)
+	h.mux.HandleFunc("POST /v2/tts/stream", h.createStream)
+	h.mux.HandleFunc("GET /v2/tts/stream/{id}", h.getStream)
+	h.mux.HandleFunc("GET /audio/{id}", h.getJobAudio)
+	h.mux.HandleFunc("GET /v2/voices", h.getVoices)
+	h.mux.HandleFunc("GET /v2/cloned-voices", h.getClonedVoices)
+	h.mux.HandleFunc("POST /v2/cloned-voices/instant", h.createClonedVoice)
+	h.mux.HandleFunc("POST /v2/cloned-voices/instant/", h.createClonedVoice)
+	h.mux.HandleFunc("DELETE /v2/cloned-voices/", h.deleteClonedVoice)
+	h.mux.HandleFunc("POST /v2/leases", h.createLease)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	h.mu.Lock()
+	h.requests = append(h.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   time.Now(),
+	})
+	failure := h.failure(r)
+	h.mu.Unlock()
+
+	if failure != nil {
+		for key, vals := range failure.Header {
+			w.Header()[key] = vals
+		}
+		ct := failure.ContentType
+		if ct == "" {
+			ct = "application/json"
+		}
+		w.Header().Set("Content-Type", ct)
+		w.WriteHeader(failure.Status)
+		_, _ = io.WriteString(w, failure.Body)
+		return
+	}
+
+	if !h.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid credentials")
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// failure returns the first scripted failure matching r.
+// NOTE: h.mu must be held by the caller.
+func (h *Handler) failure(r *http.Request) *Failure {
+	for i, f := range h.failures {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Times >= 0 {
+			f.Times--
+			if f.Times <= 0 {
+				h.failures = append(h.failures[:i], h.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	// NOTE: the returned audio URLs are accessible without credentials.
+	if r.Method == http.MethodGet &&
+		(strings.HasPrefix(r.URL.Path, "/audio/") || strings.HasPrefix(r.URL.Path, "/v2/tts/stream/")) {
+		return true
+	}
+	if h.opts.SecretKey != "" {
+		secret := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if secret != h.opts.SecretKey {
+			return false
+		}
+	}
+	if h.opts.UserID != "" && r.Header.Get(playht.UserIDHeader) != h.opts.UserID {
+		return false
+	}
+	return true
+}
+
+// Fail scripts the failures of the matching requests.
+// Failures are matched in the order they were added.
+func (h *Handler) Fail(failures ...Failure) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, f := range failures {
+		if f.Times == 0 {
+			f.Times = 1
+		}
+		h.failures = append(h.failures, &f)
+	}
+}
+
+// Requests returns all the recorded requests.
+func (h *Handler) Requests() []Request {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Request(nil), h.requests...)
+}
+
+// RequestsTo returns the recorded requests with the given method and path.
+func (h *Handler) RequestsTo(method, path string) []Request {
+	var reqs []Request
+	for _, r := range h.Requests() {
+		if r.Method == method && r.Path == path {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
+
+// Reset clears the recorded requests and scripted failures.
+func (h *Handler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = nil
+	h.failures = nil
+}
+
+// ClonedVoices returns the cloned voices.
+func (h *Handler) ClonedVoices() []playht.ClonedVoice {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]playht.ClonedVoice(nil), h.clones...)
+}
+
+// Audio returns the audio generated for params.
+func (h *Handler) Audio(params *playht.TTSParams) []byte {
+	return paramsAudio(params, h.opts.RuneDuration)
+}
+
+// paramsAudio returns the audio generated for params.
+func paramsAudio(params *playht.TTSParams, runeDuration time.Duration) []byte {
+	var sampleRate int32
+	if params.SampleRate != nil {
+		sampleRate = *params.SampleRate
+	}
+	return Audio(params.OutputFormat, sampleRate, runesDuration(params.Text, runeDuration))
+}
+
+// duration returns the audio duration generated for text.
+func (h *Handler) duration(text string) time.Duration {
+	return runesDuration(text, h.opts.RuneDuration)
+}
+
+// runesDuration returns the audio duration generated for text.
+func runesDuration(text string, d time.Duration) time.Duration {
+	return time.Duration(utf8.RuneCountInString(text)) * d
+}
+
+func (h *Handler) nextID(prefix string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	return fmt.Sprintf("%s-%d", prefix, h.seq)
+}
+
+func (h *Handler) createJob(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	input, err := params.JobReq()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	j := &job{audio: h.Audio(params)}
+	j.ID = h.nextID("job")
+	j.Created = time.Now().UTC()
+	j.Input = input
+	j.Output.Size = len(j.audio)
+	j.Output.Duration = h.duration(params.Text).Seconds()
+	j.Output.URL = baseURL(r) + "/audio/" + j.ID
+	j.Links = []playht.Link{{
+		ContentType: "application/json",
+		Description: "Fetches this job's data. Poll it for the latest status.",
+		Href:        baseURL(r) + "/v2/tts/" + j.ID,
+		Method:      http.MethodGet,
+		Rel:         "self",
+	}}
+
+	h.mu.Lock()
+	h.jobs[j.ID] = j
+	h.mu.Unlock()
+
+	if accepts(r, "text/event-stream") {
+		h.writeProgress(w, r, j, 0)
+		return
+	}
+	writeJSON(w, http.StatusCreated, h.jobStatus(j))
+}
+
+func (h *Handler) getJob(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	j, ok := h.jobs[r.PathValue("id")]
+	h.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "job not found")
+		return
+	}
+
+	switch {
+	case accepts(r, "text/event-stream"):
+		lastID, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+		h.writeProgress(w, r, j, lastID)
+	case accepts(r, "application/json"):
+		writeJSON(w, http.StatusOK, h.jobStatus(j))
+	default:
+		if j.Input.OutputFormat != "" && j.Input.OutputFormat != playht.Mp3 {
+			writeError(w, http.StatusBadRequest, "INVALID_FORMAT", "audio stream is only available for mp3 jobs")
+			return
+		}
+		h.waitJob(r, j)
+		if r.Context().Err() != nil {
+			return
+		}
+		w.Header().Set("Content-Type", ContentType(playht.Mp3))
+		h.writeAudio(w, j.audio)
+	}
+}
+
+// jobStatus returns the job with its current status.
+func (h *Handler) jobStatus(j *job) playht.TTSJob {
+	status := j.TTSJob
+	if time.Since(j.Created) < h.opts.JobDuration {
+		status.Status = playht.TTSJobGenerating
+		status.Output.URL = ""
+		status.Output.Size = 0
+		status.Output.Duration = 0
+		return status
+	}
+	status.Status = playht.TTSJobComplete
+	return status
+}
+
+// waitJob waits until the job completes or the request is canceled.
+func (h *Handler) waitJob(r *http.Request, j *job) {
+	if d := h.opts.JobDuration - time.Since(j.Created); d > 0 {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(d):
+		}
+	}
+}
+
+// writeProgress writes the job progress events with IDs greater than lastID.
+func (h *Handler) writeProgress(w http.ResponseWriter, r *http.Request, j *job, lastID int) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	step := h.opts.JobDuration / progressSteps
+	for id := 1; id <= progressSteps; id++ {
+		if d := time.Duration(id)*step - time.Since(j.Created); d > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(d):
+			}
+		}
+		if id <= lastID {
+			continue
+		}
+		writeEvent(w, progressEvent(j, id))
+		flush(w)
+	}
+
+	writeEvent(w, progressEvent(j, progressSteps+1))
+	flush(w)
+}
+
+// progressEvent returns the job progress event with the given id.
+// Events with IDs greater than progressSteps complete the job;
+// the progress of the preceding events stays below 1.
+func progressEvent(j *job, id int) *playht.JobProgressEvent {
+	if id <= progressSteps {
+		return &playht.JobProgressEvent{
+			EventID:  strconv.Itoa(id),
+			Type:     playht.JobGenerating,
+			ID:       j.ID,
+			Progress: float64(id) / (progressSteps + 1),
+			Stage:    "generate",
+		}
+	}
+	return &playht.JobProgressEvent{
+		EventID:  strconv.Itoa(id),
+		Type:     playht.JobCompleted,
+		ID:       j.ID,
+		Progress: 1,
+		Stage:    "complete",
+		URL:      j.Output.URL,
+		Duration: j.Output.Duration,
+		Size:     j.Output.Size,
+	}
+}
+
+// writeEvent writes the job progress event in the SSE format.
+func writeEvent(w io.Writer, event *playht.JobProgressEvent) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventID, event.Type, data)
+}
+
+func (h *Handler) getJobAudio(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	j, ok := h.jobs[r.PathValue("id")]
+	h.mu.Unlock()
+	if !ok || time.Since(j.Created) < h.opts.JobDuration {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType(j.Input.OutputFormat))
+	http.ServeContent(w, r, "", j.Created, bytes.NewReader(j.audio))
+}
+
+func (h *Handler) createStream(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if accepts(r, "application/json") {
+		id := h.nextID("stream")
+		h.mu.Lock()
+		h.streams[id] = params
+		h.mu.Unlock()
+
+		writeJSON(w, http.StatusCreated, playht.TTSStreamURL{
+			HRef:   baseURL(r) + "/v2/tts/stream/" + id,
+			Method: http.MethodGet,
+			CType:  ContentType(params.OutputFormat),
+			Rel:    "stream",
+			Desc:   "Stream the audio bytes.",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType(params.OutputFormat))
+	h.writeAudio(w, h.Audio(params))
+}
+
+func (h *Handler) getStream(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	params, ok := h.streams[r.PathValue("id")]
+	h.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "stream not found")
+		return
+	}
+	w.Header().Set("Content-Type", ContentType(params.OutputFormat))
+	h.writeAudio(w, h.Audio(params))
+}
+
+// writeAudio writes the audio in chunks flushing each chunk.
+func (h *Handler) writeAudio(w http.ResponseWriter, audio []byte) {
+	w.WriteHeader(http.StatusOK)
+	size := max(h.opts.ChunkSize, 1)
+	for len(audio) > 0 {
+		n := min(size, len(audio))
+		if _, err := w.Write(audio[:n]); err != nil {
+			return
+		}
+		flush(w)
+		audio = audio[n:]
+	}
+}
+
+func (h *Handler) getVoices(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.opts.Voices)
+}
+
+func (h *Handler) getClonedVoices(w http.ResponseWriter, _ *http.Request) {
+	voices := h.ClonedVoices()
+	if voices == nil {
+		voices = []playht.ClonedVoice{}
+	}
+	writeJSON(w, http.StatusOK, voices)
+}
+
+func (h *Handler) createClonedVoice(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	name := r.FormValue("voice_name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "voice_name is required")
+		return
+	}
+	if _, _, err := r.FormFile("sample_file"); err != nil && r.FormValue("sample_file_url") == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "sample_file or sample_file_url is required")
+		return
+	}
+
+	id := h.nextID("voice")
+	voice := playht.ClonedVoice{
+		ID:   "s3://voice-cloning-zero-shot/playhttest/" + id + "/manifest.json",
+		Name: name,
+		Type: "instant",
+	}
+
+	h.mu.Lock()
+	h.clones = append(h.clones, voice)
+	h.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, voice)
+}
+
+func (h *Handler) deleteClonedVoice(w http.ResponseWriter, r *http.Request) {
+	delReq := &playht.DeleteClonedVoiceRequest{}
+	if err := json.NewDecoder(r.Body).Decode(delReq); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, voice := range h.clones {
+		if voice.ID == delReq.VoiceID {
+			h.clones = append(h.clones[:i], h.clones[i+1:]...)
+			writeJSON(w, http.StatusOK, playht.DeleteClonedVoiceResp{
+				Message: "Voice deleted successfully",
+				Deleted: voice,
+			})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "NOT_FOUND", "voice not found")
+}
+
+func (h *Handler) createLease(w http.ResponseWriter, _ *http.Request) {
+	md := h.opts.LeaseMetadata
+	if md.UserID == "" {
+		md.UserID = h.opts.UserID
+	}
+	lease, err := NewLease(h.opts.LeaseKey, time.Now(), h.opts.LeaseDuration, md)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(lease)
+}
+
+// Server is the fake PlayHT API server.
+// Point the client at it via playht.WithBaseURL(server.URL).
+type Server struct {
+	*Handler
+	*httptest.Server
+}
+
+// NewServer starts a new fake PlayHT API server and returns it.
+// The server must be closed when no longer needed.
+func NewServer(opts ...Option) *Server {
+	h := NewHandler(opts...)
+	return &Server{
+		Handler: h,
+		Server:  httptest.NewServer(h),
+	}
+}
+
+// decodeParams decodes and validates the TTS params in the request body.
+func decodeParams(r *http.Request) (*playht.TTSParams, error) {
+	params := &playht.TTSParams{}
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil {
+		return nil, err
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// baseURL returns the base URL of the server which received r.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// accepts returns true if r accepts the given content type.
+func accepts(r *http.Request, contentType string) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, id, msg string) {
+	writeJSON(w, status, playht.ErrGeneric{ID: id, Message: msg})
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,217 @@
+package playhttest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/go-playht"
+	"github.com/milosgajdos/go-playht/client"
+	"github.com/milosgajdos/go-playht/playhttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(srv *playhttest.Server, opts ...playht.Option) *playht.Client {
+	opts = append([]playht.Option{
+		playht.WithBaseURL(srv.URL),
+		playht.WithSecretKey("secret"),
+		playht.WithUserID("user"),
+	}, opts...)
+	return playht.NewClient(opts...)
+}
+
+func TestServerTTSStream(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer(playhttest.WithCredentials("secret", "user"), playhttest.WithChunkSize(100))
+	defer srv.Close()
+
+	c := newClient(srv)
+	defer c.Close()
+
+	req := &playht.CreateTTSStreamReq{Text: "hello", Voice: "voice", OutputFormat: playht.Wav}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, c.TTSStream(context.Background(), buf, req))
+	assert.Equal(t, srv.Audio(playht.StreamReqParams(req)), buf.Bytes())
+
+	streamURL, err := c.TTSStreamURL(context.Background(), req)
+	assert.NoError(t, err)
+	resp, err := http.Get(streamURL.HRef)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "audio/wav", resp.Header.Get("Content-Type"))
+
+	reqs := srv.RequestsTo(http.MethodPost, "/v2/tts/stream")
+	assert.Len(t, reqs, 2)
+	assert.Equal(t, "secret", reqs[0].Header.Get("Authorization"))
+	recorded := &playht.CreateTTSStreamReq{}
+	assert.NoError(t, json.Unmarshal(reqs[0].Body, recorded))
+	assert.Equal(t, req, recorded)
+}
+
+func TestServerUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer(playhttest.WithCredentials("secret", "user"))
+	defer srv.Close()
+
+	c := newClient(srv, playht.WithSecretKey("wrong"))
+	defer c.Close()
+
+	_, err := c.GetVoices(context.Background())
+	assert.ErrorIs(t, err, playht.ErrUnauthorized)
+}
+
+func TestServerTTSJob(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer(playhttest.WithJobDuration(20 * time.Millisecond))
+	defer srv.Close()
+
+	c := newClient(srv)
+	defer c.Close()
+
+	job, err := c.CreateTTSJob(context.Background(), &playht.CreateTTSJobReq{Text: "hello", Voice: "voice"})
+	assert.NoError(t, err)
+	assert.False(t, job.Status.IsTerminal())
+
+	var events []*playht.JobProgressEvent
+	for event, err := range c.TTSJobProgressEvents(context.Background(), job.ID) {
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+	if assert.NotEmpty(t, events) {
+		last := events[len(events)-1]
+		assert.Equal(t, playht.JobCompleted, last.Type)
+		assert.Equal(t, float64(1), last.Progress)
+		// only the completed event reports the full progress
+		for _, event := range events[:len(events)-1] {
+			assert.Less(t, event.Progress, float64(1))
+		}
+	}
+
+	job, err = c.WaitForTTSJob(context.Background(), job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, playht.TTSJobComplete, job.Status)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, c.GetTTSJobAudioStream(context.Background(), buf, job.ID))
+	assert.Equal(t, job.Output.Size, buf.Len())
+
+	path := filepath.Join(t.TempDir(), "audio.mp3")
+	assert.NoError(t, os.WriteFile(path, buf.Bytes()[:100], 0o644))
+	n, err := c.DownloadTTSJobAudioFile(context.Background(), job, path)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(job.Output.Size-100), n)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+}
+
+func TestServerVoices(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	defer srv.Close()
+
+	c := newClient(srv)
+	defer c.Close()
+
+	voices, err := c.GetVoices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, playhttest.DefaultVoices, voices)
+
+	clone, err := c.CreateInstantVoiceCloneFromURL(context.Background(), &playht.CloneVoiceURLRequest{
+		SampleFileURL: "https://example.com/sample.mp3",
+		VoiceName:     "clone",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "clone", clone.Name)
+
+	clones, err := c.GetClonedVoices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []playht.ClonedVoice{*clone}, clones)
+
+	resp, err := c.DeleteClonedVoice(context.Background(), &playht.DeleteClonedVoiceRequest{VoiceID: clone.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, *clone, resp.Deleted)
+	assert.Empty(t, srv.ClonedVoices())
+
+	_, err = c.DeleteClonedVoice(context.Background(), &playht.DeleteClonedVoiceRequest{VoiceID: clone.ID})
+	assert.ErrorIs(t, err, playht.ErrNotFound)
+}
+
+func TestServerLease(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer(playhttest.WithLeaseMetadata(playht.LeaseMetadata{InferenceAddress: "localhost:1234"}))
+	defer srv.Close()
+
+	c := newClient(srv)
+	defer c.Close()
+
+	lease, err := c.CreateLease(context.Background(), &playht.CreateLeaseReq{})
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:1234", lease.Metadata.GrpcAddr(false))
+	assert.Equal(t, playhttest.DefaultLeaseDuration, lease.Duration)
+
+	_, err = playhttest.VerifyLease(playhttest.DefaultLeaseKey, lease.Data, time.Now())
+	assert.NoError(t, err)
+	_, err = playhttest.VerifyLease([]byte("wrong"), lease.Data, time.Now())
+	assert.ErrorIs(t, err, playhttest.ErrInvalidSignature)
+	_, err = playhttest.VerifyLease(playhttest.DefaultLeaseKey, lease.Data, time.Now().Add(2*time.Hour))
+	assert.ErrorIs(t, err, playhttest.ErrLeaseExpired)
+}
+
+func TestServerFailures(t *testing.T) {
+	t.Parallel()
+
+	srv := playhttest.NewServer()
+	defer srv.Close()
+
+	t.Run("rate limit", func(t *testing.T) {
+		c := newClient(srv)
+		defer c.Close()
+
+		srv.Fail(playhttest.RateLimit("/v2/voices"))
+		_, err := c.GetVoices(context.Background())
+		assert.True(t, playht.IsRateLimited(err))
+
+		_, err = c.GetVoices(context.Background())
+		assert.NoError(t, err)
+	})
+	t.Run("retry", func(t *testing.T) {
+		retry := client.DefaultRetryPolicy()
+		retry.MinBackoff = time.Millisecond
+		retry.MaxBackoff = time.Millisecond
+		c := newClient(srv, playht.WithHTTPClient(client.NewHTTP(client.WithRetry(retry))))
+		defer c.Close()
+
+		srv.Reset()
+		srv.Fail(playhttest.Failure{Path: "/v2/voices", Status: http.StatusInternalServerError, Times: 2})
+		_, err := c.GetVoices(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, srv.RequestsTo(http.MethodGet, "/v2/voices"), 3)
+	})
+	t.Run("malformed", func(t *testing.T) {
+		c := newClient(srv)
+		defer c.Close()
+
+		srv.Fail(playhttest.MalformedJSON("/v2/voices"))
+		_, err := c.GetVoices(context.Background())
+		assert.Error(t, err)
+	})
+	t.Run("internal", func(t *testing.T) {
+		c := newClient(srv)
+		defer c.Close()
+
+		srv.Fail(playhttest.InternalError(""))
+		_, err := c.GetClonedVoices(context.Background())
+		assert.True(t, playht.IsRetryable(err))
+	})
+}
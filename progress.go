@@ -0,0 +1,228 @@
+package playht
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/milosgajdos/go-playht/sse"
+)
+
+const (
+	// DefaultReconnectDelay is the default delay before
+	// reconnecting to the job progress stream.
+	DefaultReconnectDelay = 3 * time.Second
+	// DefaultMaxReconnects is the default maximum number
+	// of job progress stream reconnection attempts.
+	DefaultMaxReconnects = 3
+)
+
+// JobProgressEventType is the job progress event type.
+type JobProgressEventType string
+
+const (
+	// JobGenerating is sent while the job audio is being generated.
+	JobGenerating JobProgressEventType = "generating"
+	// JobCompleted is sent when the job has completed.
+	JobCompleted JobProgressEventType = "completed"
+	// JobError is sent when the job has failed.
+	JobError JobProgressEventType = "error"
+)
+
+func (j JobProgressEventType) String() string {
+	return string(j)
+}
+
+// known returns true if j is one of the known event types.
+func (j JobProgressEventType) known() bool {
+	switch j {
+	case JobGenerating, JobCompleted, JobError:
+		return true
+	default:
+		return false
+	}
+}
+
+// JobProgressEvent is the TTS job progress event.
+type JobProgressEvent struct {
+	// EventID is the SSE event ID.
+	EventID string `json:"-"`
+	// Type is the event type.
+	Type JobProgressEventType `json:"-"`
+	// ID is the job ID.
+	ID string `json:"id"`
+	// Progress is the job progress in the range [0, 1].
+	Progress float64 `json:"progress"`
+	// Stage is the job generation stage.
+	Stage string `json:"stage,omitempty"`
+	// StageProgress is the stage progress in the range [0, 1].
+	StageProgress float64 `json:"stage_progress,omitempty"`
+	// URL is the generated audio URL.
+	// It's only set in JobCompleted events.
+	URL string `json:"url,omitempty"`
+	// Duration is the generated audio duration in seconds.
+	// It's only set in JobCompleted events.
+	Duration float64 `json:"duration,omitempty"`
+	// Size is the generated audio size in bytes.
+	// It's only set in JobCompleted events.
+	Size int `json:"size,omitempty"`
+	// Error is the job error message.
+	// It's only set in JobError events.
+	Error string `json:"error,omitempty"`
+}
+
+// IsTerminal returns true if the event is the last event in the stream.
+func (e *JobProgressEvent) IsTerminal() bool {
+	return e.Type == JobCompleted || e.Type == JobError
+}
+
+// JobProgressOptions configure the job progress stream.
+type JobProgressOptions struct {
+	// MaxReconnects is the maximum number of reconnection
+	// attempts when the stream ends prematurely.
+	MaxReconnects int
+	// ReconnectDelay is the delay before reconnecting.
+	// It's overridden by the retry time sent by the server.
+	ReconnectDelay time.Duration
+}
+
+// JobProgressOption is a job progress stream functional option.
+type JobProgressOption func(*JobProgressOptions)
+
+// WithMaxReconnects sets the maximum number of job progress stream reconnection attempts.
+func WithMaxReconnects(n int) JobProgressOption {
+	return func(o *JobProgressOptions) {
+		o.MaxReconnects = n
+	}
+}
+
+// WithReconnectDelay sets the delay before reconnecting to the job progress stream.
+func WithReconnectDelay(d time.Duration) JobProgressOption {
+	return func(o *JobProgressOptions) {
+		o.ReconnectDelay = d
+	}
+}
+
+// TTSJobProgressEvents returns an iterator over the progress events of the job with the given id.
+// Events of unknown types, such as keep-alive pings, are skipped.
+// The iteration ends after JobCompleted or JobError event. If the stream ends prematurely,
+// it is resumed by reconnecting with the Last-Event-ID header set to the last received event ID.
+func (c *Client) TTSJobProgressEvents(ctx context.Context, id string, opts ...JobProgressOption) iter.Seq2[*JobProgressEvent, error] {
+	return c.progressEvents(ctx, id, func(ctx context.Context) (*http.Response, error) {
+		return c.getTTSJobProgress(ctx, id, "")
+	}, opts...)
+}
+
+// CreateTTSJobWithProgressEvents creates a new TTS job and returns an iterator over its progress events.
+// The events are handled the same way as in TTSJobProgressEvents.
+func (c *Client) CreateTTSJobWithProgressEvents(ctx context.Context, createReq *CreateTTSJobReq, opts ...JobProgressOption) iter.Seq2[*JobProgressEvent, error] {
+	return c.progressEvents(ctx, "", func(ctx context.Context) (*http.Response, error) {
+		return c.createTTSJobProgress(ctx, createReq)
+	}, opts...)
+}
+
+// progressEvents returns the job progress event iterator.
+// The first stream is opened via open; reconnections
+// use the job ID learned from the received events.
+func (c *Client) progressEvents(ctx context.Context, id string, open func(context.Context) (*http.Response, error), opts ...JobProgressOption) iter.Seq2[*JobProgressEvent, error] {
+	options := JobProgressOptions{
+		MaxReconnects:  DefaultMaxReconnects,
+		ReconnectDelay: DefaultReconnectDelay,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	return func(yield func(*JobProgressEvent, error) bool) {
+		var lastID string
+		reconnects := 0
+
+		for {
+			resp, err := open(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			dec := sse.NewDecoder(resp.Body)
+			for {
+				var event *sse.Event
+				event, err = dec.Decode()
+				if err != nil {
+					break
+				}
+				reconnects = 0
+
+				// NOTE: keep-alive and unknown events are skipped
+				if !JobProgressEventType(event.Event).known() {
+					continue
+				}
+
+				var pe *JobProgressEvent
+				pe, err = parseJobProgressEvent(event)
+				if err != nil {
+					resp.Body.Close()
+					yield(nil, err)
+					return
+				}
+				if id == "" {
+					id = pe.ID
+				}
+				if !yield(pe, nil) || pe.IsTerminal() {
+					resp.Body.Close()
+					return
+				}
+			}
+			resp.Body.Close()
+
+			if ctx.Err() != nil {
+				yield(nil, ctx.Err())
+				return
+			}
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			if reconnects >= options.MaxReconnects || id == "" {
+				yield(nil, err)
+				return
+			}
+			reconnects++
+
+			if retry := dec.Retry(); retry > 0 {
+				options.ReconnectDelay = retry
+			}
+			if lid := dec.LastEventID(); lid != "" {
+				lastID = lid
+			}
+			open = func(ctx context.Context) (*http.Response, error) {
+				return c.getTTSJobProgress(ctx, id, lastID)
+			}
+
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			case <-time.After(options.ReconnectDelay):
+			}
+		}
+	}
+}
+
+// parseJobProgressEvent parses the job progress event from the SSE event.
+func parseJobProgressEvent(event *sse.Event) (*JobProgressEvent, error) {
+	pe := &JobProgressEvent{}
+	if err := json.Unmarshal([]byte(event.Data), pe); err != nil {
+		if event.Event != string(JobError) {
+			return nil, fmt.Errorf("failed decoding %s event: %v", event.Event, err)
+		}
+		// NOTE: error events are not guaranteed to be JSON
+		pe.Error = event.Data
+	}
+	pe.EventID = event.ID
+	pe.Type = JobProgressEventType(event.Event)
+	return pe, nil
+}
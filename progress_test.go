@@ -0,0 +1,50 @@
+package playht
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTSJobProgressEvents(t *testing.T) {
+	t.Parallel()
+
+	var conns atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if conns.Add(1) == 1 {
+			// NOTE: the stream ends before the job has completed
+			fmt.Fprint(w, "id: 1\nevent: generating\ndata: {\"id\":\"job\",\"progress\":0.5,\"stage\":\"generate\"}\n\n")
+			return
+		}
+		assert.Equal(t, "1", r.Header.Get("Last-Event-ID"))
+		// NOTE: keep-alive and unknown events must be skipped
+		fmt.Fprint(w, "event: ping\ndata: ping\n\n")
+		fmt.Fprint(w, "event: unknown\ndata: {\"foo\":\"bar\"}\n\n")
+		fmt.Fprint(w, "id: 2\nevent: completed\ndata: {\"id\":\"job\",\"progress\":1,\"url\":\"https://foo/bar.mp3\",\"duration\":1.5,\"size\":100}\n\n")
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	defer c.Close()
+
+	var events []*JobProgressEvent
+	for event, err := range c.TTSJobProgressEvents(context.Background(), "job", WithReconnectDelay(time.Millisecond)) {
+		assert.NoError(t, err)
+		events = append(events, event)
+	}
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, JobGenerating, events[0].Type)
+	assert.Equal(t, 0.5, events[0].Progress)
+	assert.Equal(t, JobCompleted, events[1].Type)
+	assert.Equal(t, "2", events[1].EventID)
+	assert.Equal(t, "https://foo/bar.mp3", events[1].URL)
+	assert.Equal(t, 100, events[1].Size)
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/milosgajdos/go-playht/client"
 )
@@ -46,7 +49,66 @@ func NewHTTP(ctx context.Context, method, url string, body io.Reader, opts ...HT
 	return req, nil
 }
 
+const (
+	// MaxErrorBodySize is the maximum number of bytes
+	// read from the API error response body.
+	MaxErrorBodySize = 64 * 1024
+	// MaxErrorSnippetSize is the maximum number of
+	// the response body bytes included in error messages.
+	MaxErrorSnippetSize = 512
+)
+
+// ResponseError is implemented by errors which carry
+// the details of the HTTP response they were decoded from.
+type ResponseError interface {
+	error
+	// SetResponse sets the error response details.
+	SetResponse(*http.Response)
+}
+
+// RawBodyError is implemented by errors which can hold
+// the response body which could not be decoded as JSON.
+type RawBodyError interface {
+	error
+	// SetRawBody sets the response body and its content type.
+	SetRawBody(contentType string, body []byte)
+}
+
+// Error is returned by Do when the error response body
+// can't be decoded into the requested error type.
+type Error struct {
+	// StatusCode is the HTTP response status code.
+	StatusCode int
+	// ContentType is the response content type.
+	ContentType string
+	// Body is the response body truncated to MaxErrorBodySize.
+	Body []byte
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if len(e.Body) > 0 {
+		msg += ": " + Snippet(e.Body, MaxErrorSnippetSize)
+	}
+	return msg
+}
+
+// Snippet returns the first n bytes of body as a string.
+// Invalid UTF-8 is dropped and the truncation is marked with an ellipsis.
+func Snippet(body []byte, n int) string {
+	if len(body) <= n {
+		return strings.ToValidUTF8(string(body), "")
+	}
+	return strings.ToValidUTF8(string(body[:n]), "") + "..."
+}
+
 // Do sends the HTTP request req using the client and returns the response.
+// If the API responds with an error status code, the response body is decoded into T.
+// If T implements ResponseError, the response details are set on it. Response bodies
+// which are not JSON (e.g. HTML or empty bodies returned by proxies) are set on T
+// if it implements RawBodyError; otherwise *Error is returned.
+// The error response body is always drained and closed so the connection can be reused.
 func Do[T error](client *client.HTTP, req *http.Request) (*http.Response, error) {
 	resp, err := client.Do(req)
 	if err != nil {
@@ -55,15 +117,82 @@ func Do[T error](client *client.HTTP, req *http.Request) (*http.Response, error)
 	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
 		return resp, nil
 	}
+	defer func() {
+		// NOTE: draining the body lets the connection be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MaxErrorBodySize))
+		resp.Body.Close()
+	}()
 
-	var apiErr T
-	if jsonErr := json.NewDecoder(resp.Body).Decode(&apiErr); jsonErr != nil {
-		return nil, jsonErr
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize))
+	if err != nil {
+		return nil, err
+	}
+	ct := resp.Header.Get("Content-Type")
+
+	apiErr := newError[T]()
+	if isJSON(ct, body) {
+		if err := json.Unmarshal(body, decodeTarget(&apiErr)); err == nil {
+			if respErr, ok := any(apiErr).(ResponseError); ok {
+				respErr.SetResponse(resp)
+			}
+			return nil, apiErr
+		}
+		apiErr = newError[T]()
+	}
+
+	rawErr, ok := any(apiErr).(RawBodyError)
+	if !ok {
+		return nil, &Error{
+			StatusCode:  resp.StatusCode,
+			ContentType: ct,
+			Body:        body,
+		}
+	}
+	rawErr.SetRawBody(ct, body)
+	if respErr, ok := any(apiErr).(ResponseError); ok {
+		respErr.SetResponse(resp)
 	}
 
 	return nil, apiErr
 }
 
+// isJSON returns true if the body with the given content type is JSON.
+// If the content type is missing, the body is sniffed.
+func isJSON(contentType string, body []byte) bool {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return false
+	}
+	if contentType == "" {
+		b := bytes.TrimSpace(body)
+		return b[0] == '{' || b[0] == '['
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// newError returns a new error of type T.
+// If T is a pointer type, the pointer is allocated.
+func newError[T error]() T {
+	var e T
+	if t := reflect.TypeFor[T](); t.Kind() == reflect.Pointer {
+		e = reflect.New(t.Elem()).Interface().(T)
+	}
+	return e
+}
+
+// decodeTarget returns the JSON decoding target for e.
+// Allocated pointers are decoded into directly so
+// they remain allocated even if the body is null.
+func decodeTarget[T error](e *T) any {
+	if reflect.TypeFor[T]().Kind() == reflect.Pointer {
+		return *e
+	}
+	return e
+}
+
 // HTTPOption is a HTTP request functional option.
 type HTTPOption func(*http.Request)
 
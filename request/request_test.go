@@ -3,13 +3,35 @@ package request
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/milosgajdos/go-playht/client"
 	"github.com/stretchr/testify/assert"
 )
 
+type testError struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"-"`
+	Body       string `json:"-"`
+}
+
+func (e *testError) Error() string { return e.Message }
+
+func (e *testError) SetResponse(resp *http.Response) { e.StatusCode = resp.StatusCode }
+
+func (e *testError) SetRawBody(_ string, body []byte) { e.Body = string(body) }
+
+type jsonError struct {
+	Message string `json:"message"`
+}
+
+func (e *jsonError) Error() string { return e.Message }
+
 func TestNewHTTPRequest(t *testing.T) {
 	t.Parallel()
 	t.Run("nil context", func(t *testing.T) {
@@ -81,3 +103,73 @@ func TestHTTPReqOption(t *testing.T) {
 		assert.Equal(t, req.Header.Values(key), []string{val, val})
 	})
 }
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		ct     string
+		status int
+		body   string
+		want   *testError
+	}{
+		{"json", "application/json", http.StatusBadRequest, `{"message":"bad"}`, &testError{Message: "bad", StatusCode: http.StatusBadRequest}},
+		{"sniffed json", "", http.StatusBadRequest, `{"message":"bad"}`, &testError{Message: "bad", StatusCode: http.StatusBadRequest}},
+		{"malformed json", "application/json", http.StatusBadGateway, `{"message":`, &testError{StatusCode: http.StatusBadGateway, Body: `{"message":`}},
+		{"html", "text/html", http.StatusBadGateway, `<html>bad gateway</html>`, &testError{StatusCode: http.StatusBadGateway, Body: `<html>bad gateway</html>`}},
+		{"empty", "", http.StatusServiceUnavailable, "", &testError{StatusCode: http.StatusServiceUnavailable}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header()["Content-Type"] = []string{tc.ct}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			req, err := NewHTTP(context.TODO(), http.MethodGet, srv.URL, nil)
+			assert.NoError(t, err)
+			// nolint:bodyclose
+			resp, err := Do[*testError](client.NewHTTP(), req)
+			assert.Nil(t, resp)
+			var apiErr *testError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tc.want, apiErr)
+		})
+	}
+
+	t.Run("fallback error", func(t *testing.T) {
+		t.Parallel()
+		body := strings.Repeat("x", MaxErrorSnippetSize+1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, body)
+		}))
+		defer srv.Close()
+
+		req, err := NewHTTP(context.TODO(), http.MethodGet, srv.URL, nil)
+		assert.NoError(t, err)
+		// nolint:bodyclose
+		_, err = Do[*jsonError](client.NewHTTP(), req)
+		var respErr *Error
+		assert.True(t, errors.As(err, &respErr))
+		assert.Equal(t, http.StatusInternalServerError, respErr.StatusCode)
+		assert.Equal(t, body, string(respErr.Body))
+		assert.True(t, strings.HasSuffix(respErr.Error(), "..."))
+	})
+}
+
+func TestIsJSON(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isJSON("application/json; charset=utf-8", []byte("{}")))
+	assert.True(t, isJSON("application/problem+json", []byte("{}")))
+	assert.True(t, isJSON("", []byte(" [1]")))
+	assert.False(t, isJSON("application/json", nil))
+	assert.False(t, isJSON("text/html", []byte("{}")))
+	assert.False(t, isJSON("", []byte("<html>")))
+}
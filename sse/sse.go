@@ -0,0 +1,150 @@
+// Package sse implements Server-Sent Events stream decoder.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultEvent is the default event type.
+	DefaultEvent = "message"
+	// MaxLineSize is the maximum size of the event stream line.
+	MaxLineSize = 1024 * 1024
+)
+
+// Event is a Server-Sent Event.
+type Event struct {
+	// ID is the event ID.
+	ID string
+	// Event is the event type.
+	Event string
+	// Data is the event data.
+	// Multiple data lines are joined with a newline.
+	Data string
+	// Retry is the reconnection time requested by the server.
+	// It's zero if the event didn't contain the retry field.
+	Retry time.Duration
+}
+
+// Decoder decodes Server-Sent Events from the event stream.
+type Decoder struct {
+	s      *bufio.Scanner
+	lastID string
+	retry  time.Duration
+}
+
+// NewDecoder creates a new Decoder reading the event stream from r and returns it.
+func NewDecoder(r io.Reader) *Decoder {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), MaxLineSize)
+	s.Split(scanLines)
+	return &Decoder{
+		s: s,
+	}
+}
+
+// Decode decodes the next event from the stream.
+// It returns io.EOF when the stream ends.
+// Incomplete events at the end of the stream are discarded.
+func (d *Decoder) Decode() (*Event, error) {
+	var (
+		data    strings.Builder
+		hasData bool
+		event   string
+		retry   time.Duration
+	)
+
+	for d.s.Scan() {
+		line := d.s.Text()
+		if line == "" {
+			if !hasData {
+				event, retry = "", 0
+				continue
+			}
+			if event == "" {
+				event = DefaultEvent
+			}
+			return &Event{
+				ID:    d.lastID,
+				Event: event,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: retry,
+			}, nil
+		}
+
+		// lines starting with colon are comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			hasData = true
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastID = value
+			}
+		case "retry":
+			ms, err := strconv.ParseUint(value, 10, 63)
+			if err == nil {
+				retry = time.Duration(ms) * time.Millisecond
+				d.retry = retry
+			}
+		}
+	}
+
+	if err := d.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// LastEventID returns the last event ID received in the stream.
+// It should be sent in the Last-Event-ID header when reconnecting.
+func (d *Decoder) LastEventID() string {
+	return d.lastID
+}
+
+// Retry returns the last reconnection time requested by the server.
+// It's zero if the server never requested any.
+func (d *Decoder) Retry() time.Duration {
+	return d.retry
+}
+
+// scanLines is a bufio.SplitFunc which splits
+// the stream on CRLF, LF and CR line endings.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// NOTE: we need more data to tell CR from CRLF
+		if i+1 == len(data) && !atEOF {
+			return 0, nil, nil
+		}
+		if i+1 < len(data) && data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
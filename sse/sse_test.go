@@ -0,0 +1,86 @@
+package sse
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		stream string
+		events []Event
+		lastID string
+	}{
+		{
+			name:   "empty",
+			stream: "",
+		},
+		{
+			name:   "default event",
+			stream: "data: foo\n\n",
+			events: []Event{{Event: DefaultEvent, Data: "foo"}},
+		},
+		{
+			name:   "multiline data",
+			stream: "event: bar\ndata: foo\ndata:baz\n\n",
+			events: []Event{{Event: "bar", Data: "foo\nbaz"}},
+		},
+		{
+			name:   "comments and unknown fields",
+			stream: ": comment\nfoo: bar\ndata: foo\n\n",
+			events: []Event{{Event: DefaultEvent, Data: "foo"}},
+		},
+		{
+			name:   "id and retry",
+			stream: "id: 1\nretry: 1500\ndata: foo\n\ndata: bar\n\n",
+			events: []Event{
+				{ID: "1", Event: DefaultEvent, Data: "foo", Retry: 1500 * time.Millisecond},
+				{ID: "1", Event: DefaultEvent, Data: "bar"},
+			},
+			lastID: "1",
+		},
+		{
+			name:   "crlf and cr",
+			stream: "event: foo\r\ndata: bar\r\n\r\nevent: baz\rdata: qux\r\r",
+			events: []Event{
+				{Event: "foo", Data: "bar"},
+				{Event: "baz", Data: "qux"},
+			},
+		},
+		{
+			name:   "no data",
+			stream: "event: foo\n\ndata: bar\n\n",
+			events: []Event{{Event: DefaultEvent, Data: "bar"}},
+		},
+		{
+			name:   "incomplete event",
+			stream: "data: foo\n\ndata: bar\n",
+			events: []Event{{Event: DefaultEvent, Data: "foo"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dec := NewDecoder(strings.NewReader(tc.stream))
+			var events []Event
+			for {
+				event, err := dec.Decode()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				events = append(events, *event)
+			}
+			assert.Equal(t, tc.events, events)
+			assert.Equal(t, tc.lastID, dec.LastEventID())
+		})
+	}
+}
@@ -20,13 +20,13 @@ type CreateTTSStreamReq struct {
 	OutputFormat  OutputFormat `json:"output_format,omitempty"`
 	VoiceEngine   VoiceEngine  `json:"voice_engine,omitempty"`
 	Emotion       Emotion      `json:"emotion,omitempty"`
-	SampleRate    int32        `json:"sample_rate"`
+	SampleRate    int32        `json:"sample_rate,omitempty"`
 	Seed          int32        `json:"seed,omitempty"`
 	VoiceGuidance float32      `json:"voice_guidance,omitempty"`
 	StyleGuidance float32      `json:"style_guidance,omitempty"`
 	TextGuidance  float32      `json:"text_guidance,omitempty"`
 	Temperature   float32      `json:"temperature,omitempty"`
-	Speed         float32      `json:"speed"`
+	Speed         float32      `json:"speed,omitempty"`
 }
 
 // TTSStreamURL is returned when the stream URL is requested.
@@ -38,39 +38,157 @@ type TTSStreamURL struct {
 	Desc   string `json:"description"`
 }
 
+// GrpcStreamOptions configure the gRPC stream.
+type GrpcStreamOptions struct {
+	// StatusFunc is called with every status received in the stream.
+	StatusFunc func(sequence int32, status *pb.Status)
+	// SequenceCheck enables detection of the chunk sequence gaps
+	// and out of order chunks which are reported as *SequenceError.
+	SequenceCheck bool
+	// ReorderWindow is the maximum number of out of order
+	// chunks held back until the missing chunks arrive.
+	ReorderWindow int
+}
+
+// GrpcStreamOption is a gRPC stream functional option.
+type GrpcStreamOption func(*GrpcStreamOptions)
+
+// WithStatusFunc sets the func called with every status received in the stream.
+// It can be used to monitor the stream progress.
+func WithStatusFunc(fn func(sequence int32, status *pb.Status)) GrpcStreamOption {
+	return func(o *GrpcStreamOptions) {
+		o.StatusFunc = fn
+	}
+}
+
+// WithSequenceCheck enables detection of the chunk sequence gaps and out of order chunks.
+func WithSequenceCheck() GrpcStreamOption {
+	return func(o *GrpcStreamOptions) {
+		o.SequenceCheck = true
+	}
+}
+
+// WithReorder enables reordering of out of order chunks.
+// Up to window chunks are held back until the missing chunks arrive.
+// It implies WithSequenceCheck.
+func WithReorder(window int) GrpcStreamOption {
+	return func(o *GrpcStreamOptions) {
+		o.SequenceCheck = true
+		o.ReorderWindow = window
+	}
+}
+
 // TTSGrpcStream creates a new TTS stream ovr gRCP and streams the audio bytes immediately.
-func (c *Client) TTSGrpcStream(ctx context.Context, w io.Writer, req *pb.TtsRequest) error {
-	ttsc := pb.NewTtsClient(c.opts.GRPC)
-	tts, err := ttsc.Tts(ctx, req)
+// If req does not contain any lease, the lease is obtained from the client LeaseManager.
+// The stream ends when the stream completion status is received. If the stream reports
+// an error or cancelation status, *StreamStatusError is returned.
+func (c *Client) TTSGrpcStream(ctx context.Context, w io.Writer, req *pb.TtsRequest, opts ...GrpcStreamOption) error {
+	r, err := c.TTSGrpcStreamReader(ctx, req, opts...)
 	if err != nil {
 		return err
 	}
-	for {
-		resp, err := tts.Recv()
-		if err != nil {
-			if err == io.EOF {
-				return nil
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TTSGrpcStreamReader creates a new TTS stream over gRPC and returns the stream audio reader
+// as soon as the stream has been opened. The audio bytes are received lazily as they're read.
+// Stream errors are returned by Read. Closing the reader cancels the stream.
+// If req does not contain any lease, the lease is obtained from the client LeaseManager.
+// Stream status is handled the same way as in TTSGrpcStream.
+func (c *Client) TTSGrpcStreamReader(ctx context.Context, req *pb.TtsRequest, opts ...GrpcStreamOption) (io.ReadCloser, error) {
+	chunks, err := c.TTSGrpcChunks(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcStreamReader{
+		chunks: chunks,
+	}, nil
+}
+
+// grpcStreamReader reads the audio bytes from the gRPC TTS stream.
+type grpcStreamReader struct {
+	chunks *ChunkStream
+	buf    []byte
+}
+
+// Read implements io.Reader.
+func (r *grpcStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if !r.chunks.Next() {
+			if err := r.chunks.Err(); err != nil {
+				return 0, err
 			}
-			return err
-		}
-		if _, err := io.Copy(w, bytes.NewBuffer(resp.Data)); err != nil {
-			return err
+			return 0, io.EOF
 		}
+		r.buf = r.chunks.Chunk().Data
 	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close implements io.Closer.
+// It cancels the stream.
+func (r *grpcStreamReader) Close() error {
+	return r.chunks.Close()
+}
+
+// withLease returns req with the lease set to the lease obtained
+// from the client LeaseManager if req does not contain any lease.
+func (c *Client) withLease(ctx context.Context, req *pb.TtsRequest) (*pb.TtsRequest, error) {
+	if len(req.GetLease()) > 0 {
+		return req, nil
+	}
+	lease, err := c.leases.Lease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TtsRequest{
+		Lease:  lease.Data,
+		Params: req.GetParams(),
+	}, nil
 }
 
 // TTSStream creates a new TTS stream and streams the audio bytes immediately.
 func (c *Client) TTSStream(ctx context.Context, w io.Writer, createReq *CreateTTSStreamReq) error {
-	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts/stream")
+	r, err := c.TTSStreamReader(ctx, createReq)
 	if err != nil {
 		return err
 	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TTSStreamReader creates a new TTS stream and returns the stream audio reader
+// as soon as the API has responded. The audio bytes are received lazily as they're read.
+// Closing the reader cancels the stream.
+func (c *Client) TTSStreamReader(ctx context.Context, createReq *CreateTTSStreamReq) (io.ReadCloser, error) {
+	return c.ttsStreamReader(ctx, createReq)
+}
+
+// ttsStreamReader creates a new TTS stream with the given JSON request body and returns the stream audio reader.
+func (c *Client) ttsStreamReader(ctx context.Context, createReq any) (io.ReadCloser, error) {
+	u, err := url.Parse(c.opts.BaseURL + "/" + c.opts.Version + "/tts/stream")
+	if err != nil {
+		return nil, err
+	}
 
 	var body = &bytes.Buffer{}
 	enc := json.NewEncoder(body)
 	enc.SetEscapeHTML(false)
 	if err := enc.Encode(createReq); err != nil {
-		return err
+		return nil, err
 	}
 
 	options := []request.HTTPOption{
@@ -81,19 +199,15 @@ func (c *Client) TTSStream(ctx context.Context, w io.Writer, createReq *CreateTT
 
 	req, err := request.NewHTTP(ctx, http.MethodPost, u.String(), body, options...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := request.Do[*APIError](c.opts.HTTPClient, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if _, err := io.Copy(w, resp.Body); err != nil {
-		return err
-	}
-	return nil
+	return resp.Body, nil
 }
 
 // TTSStreamURL creates a new TTS stream and returns data containing an URL that is immediately streamable.
@@ -0,0 +1,276 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTtsStream replays the responses and then returns err.
+type fakeTtsStream struct {
+	grpc.ClientStream
+	resps []*pb.TtsResponse
+	err   error
+}
+
+func (s *fakeTtsStream) Trailer() metadata.MD {
+	return metadata.Pairs("foo", "bar")
+}
+
+func (s *fakeTtsStream) Recv() (*pb.TtsResponse, error) {
+	if len(s.resps) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	resp := s.resps[0]
+	s.resps = s.resps[1:]
+	return resp, nil
+}
+
+func TestGrpcStreamReader(t *testing.T) {
+	t.Parallel()
+	t.Run("read", func(t *testing.T) {
+		t.Parallel()
+		stream := &fakeTtsStream{
+			resps: []*pb.TtsResponse{
+				{Data: []byte("foo")},
+				{Data: nil},
+				{Data: []byte("bar")},
+			},
+		}
+		_, cancel := context.WithCancel(context.Background())
+		r := &grpcStreamReader{chunks: newChunkStream(stream, cancel, GrpcStreamOptions{})}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "foobar", string(data))
+	})
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		streamErr := errors.New("stream error")
+		stream := &fakeTtsStream{
+			resps: []*pb.TtsResponse{{Data: []byte("foo")}},
+			err:   streamErr,
+		}
+		_, cancel := context.WithCancel(context.Background())
+		r := &grpcStreamReader{chunks: newChunkStream(stream, cancel, GrpcStreamOptions{})}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		assert.ErrorIs(t, err, streamErr)
+		assert.Equal(t, "foo", string(data))
+	})
+}
+
+func TestGrpcStreamReaderStatus(t *testing.T) {
+	t.Parallel()
+	t.Run("complete", func(t *testing.T) {
+		t.Parallel()
+		stream := &fakeTtsStream{
+			resps: []*pb.TtsResponse{
+				{Sequence: 1, Data: []byte("foo"), Status: &pb.Status{Code: pb.Code_CODE_IN_PROGRESS}},
+				{Sequence: 2, Data: []byte("bar"), Status: &pb.Status{Code: pb.Code_CODE_COMPLETE}},
+				{Sequence: 3, Data: []byte("baz")},
+			},
+		}
+		var codes []pb.Code
+		opts := GrpcStreamOptions{
+			StatusFunc: func(_ int32, status *pb.Status) {
+				codes = append(codes, status.Code)
+			},
+		}
+		_, cancel := context.WithCancel(context.Background())
+		r := &grpcStreamReader{chunks: newChunkStream(stream, cancel, opts)}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "foobar", string(data))
+		assert.Equal(t, []pb.Code{pb.Code_CODE_IN_PROGRESS, pb.Code_CODE_COMPLETE}, codes)
+	})
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		stream := &fakeTtsStream{
+			resps: []*pb.TtsResponse{
+				{Sequence: 1, Data: []byte("foo")},
+				{Status: &pb.Status{Code: pb.Code_CODE_ERROR, Message: []string{"boom"}}},
+			},
+		}
+		_, cancel := context.WithCancel(context.Background())
+		r := &grpcStreamReader{chunks: newChunkStream(stream, cancel, GrpcStreamOptions{})}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		assert.Equal(t, "foo", string(data))
+		var statusErr *StreamStatusError
+		assert.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, pb.Code_CODE_ERROR, statusErr.Code)
+		assert.Equal(t, []string{"boom"}, statusErr.Messages)
+		assert.Equal(t, int32(1), statusErr.Sequence)
+	})
+}
+
+func TestChunkStream(t *testing.T) {
+	t.Parallel()
+
+	chunk := func(seq int32, data string) *pb.TtsResponse {
+		return &pb.TtsResponse{Sequence: seq, Id: "id", Data: []byte(data)}
+	}
+	complete := &pb.TtsResponse{Status: &pb.Status{Code: pb.Code_CODE_COMPLETE}}
+
+	testCases := []struct {
+		name  string
+		resps []*pb.TtsResponse
+		opts  GrpcStreamOptions
+		seqs  []int32
+		err   *SequenceError
+	}{
+		{
+			name:  "in order",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(2, "b"), complete},
+			opts:  GrpcStreamOptions{SequenceCheck: true},
+			seqs:  []int32{1, 2, 0},
+		},
+		{
+			name:  "unchecked gap",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(3, "c"), complete},
+			seqs:  []int32{1, 3, 0},
+		},
+		{
+			name:  "gap",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(3, "c"), complete},
+			opts:  GrpcStreamOptions{SequenceCheck: true},
+			seqs:  []int32{1},
+			err:   &SequenceError{Expected: 2, Got: 3},
+		},
+		{
+			name:  "reorder",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(3, "c"), chunk(2, "b"), complete},
+			opts:  GrpcStreamOptions{SequenceCheck: true, ReorderWindow: 2},
+			seqs:  []int32{1, 2, 3, 0},
+		},
+		{
+			name:  "reorder gap",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(3, "c"), complete},
+			opts:  GrpcStreamOptions{SequenceCheck: true, ReorderWindow: 2},
+			seqs:  []int32{1},
+			err:   &SequenceError{Expected: 2, Got: 3},
+		},
+		{
+			name:  "reorder held status",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(3, "c"), complete, chunk(2, "b")},
+			opts:  GrpcStreamOptions{SequenceCheck: true, ReorderWindow: 2},
+			seqs:  []int32{1, 2, 3, 0},
+		},
+		{
+			name: "gap with completing chunk",
+			resps: []*pb.TtsResponse{
+				chunk(1, "a"),
+				{Sequence: 3, Id: "id", Data: []byte("c"), Status: &pb.Status{Code: pb.Code_CODE_COMPLETE}},
+			},
+			opts: GrpcStreamOptions{SequenceCheck: true},
+			seqs: []int32{1},
+			err:  &SequenceError{Expected: 2, Got: 3},
+		},
+		{
+			name:  "duplicate",
+			resps: []*pb.TtsResponse{chunk(1, "a"), chunk(1, "a"), complete},
+			opts:  GrpcStreamOptions{SequenceCheck: true, ReorderWindow: 2},
+			seqs:  []int32{1},
+			err:   &SequenceError{Expected: 2, Got: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, cancel := context.WithCancel(context.Background())
+			chunks := newChunkStream(&fakeTtsStream{resps: tc.resps}, cancel, tc.opts)
+			defer chunks.Close()
+
+			var seqs []int32
+			for chunks.Next() {
+				seqs = append(seqs, chunks.Chunk().Sequence)
+			}
+			assert.Equal(t, tc.seqs, seqs)
+			if tc.err == nil {
+				assert.NoError(t, chunks.Err())
+				return
+			}
+			var seqErr *SequenceError
+			assert.ErrorAs(t, chunks.Err(), &seqErr)
+			assert.Equal(t, tc.err, seqErr)
+		})
+	}
+}
+
+func TestChunkStreamLeaseExpired(t *testing.T) {
+	t.Parallel()
+
+	expired := status.Error(codes.Unauthenticated, "lease expired")
+	t.Run("reopen", func(t *testing.T) {
+		t.Parallel()
+		_, cancel := context.WithCancel(context.Background())
+		chunks := newChunkStream(&fakeTtsStream{err: expired}, cancel, GrpcStreamOptions{})
+		defer chunks.Close()
+		reopened := 0
+		chunks.reopen = func() (pb.Tts_TtsClient, error) {
+			reopened++
+			return &fakeTtsStream{resps: []*pb.TtsResponse{{Sequence: 1, Data: []byte("foo")}}}, nil
+		}
+
+		assert.True(t, chunks.Next())
+		assert.Equal(t, "foo", string(chunks.Chunk().Data))
+		assert.False(t, chunks.Next())
+		assert.NoError(t, chunks.Err())
+		assert.Equal(t, 1, reopened)
+	})
+	t.Run("reopen once", func(t *testing.T) {
+		t.Parallel()
+		_, cancel := context.WithCancel(context.Background())
+		chunks := newChunkStream(&fakeTtsStream{err: expired}, cancel, GrpcStreamOptions{})
+		defer chunks.Close()
+		chunks.reopen = func() (pb.Tts_TtsClient, error) {
+			return &fakeTtsStream{err: expired}, nil
+		}
+
+		assert.False(t, chunks.Next())
+		assert.ErrorIs(t, chunks.Err(), ErrLeaseExpired)
+		assert.ErrorIs(t, chunks.Err(), ErrUnauthorized)
+		var grpcErr *GrpcError
+		assert.ErrorAs(t, chunks.Err(), &grpcErr)
+		assert.Equal(t, codes.Unauthenticated, grpcErr.Code)
+		assert.Equal(t, []string{"bar"}, grpcErr.Trailer.Get("foo"))
+		assert.True(t, IsAuthError(chunks.Err()))
+	})
+}
+
+func TestGrpcError(t *testing.T) {
+	t.Parallel()
+	err := NewGrpcError(status.Error(codes.ResourceExhausted, "slow down"), nil)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.NotErrorIs(t, err, ErrLeaseExpired)
+	assert.True(t, IsRateLimited(err))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	assert.Equal(t, io.EOF, NewGrpcError(io.EOF, nil))
+	assert.ErrorIs(t, NewGrpcError(status.Error(codes.PermissionDenied, "denied"), nil), ErrForbidden)
+	assert.ErrorIs(t, NewGrpcError(status.Error(codes.Unauthenticated, "invalid lease"), nil), ErrLeaseExpired)
+	assert.ErrorIs(t, NewGrpcError(status.Error(codes.Unauthenticated, "Lease expired"), nil), ErrLeaseExpired)
+	// only Unauthenticated errors are considered lease errors
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.PermissionDenied, codes.FailedPrecondition} {
+		assert.NotErrorIs(t, NewGrpcError(status.Error(code, "invalid lease"), nil), ErrLeaseExpired, code.String())
+	}
+	assert.NotErrorIs(t, NewGrpcError(status.Error(codes.Unauthenticated, "invalid token"), nil), ErrLeaseExpired)
+}
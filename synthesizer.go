@@ -0,0 +1,221 @@
+package playht
+
+import (
+	"context"
+	"io"
+	"time"
+
+	pb "github.com/milosgajdos/go-playht/proto"
+)
+
+// Transport is the transport used to synthesize the speech.
+type Transport string
+
+const (
+	// TransportHTTP streams the audio over HTTP.
+	TransportHTTP Transport = "http"
+	// TransportGRPC streams the audio over gRPC.
+	TransportGRPC Transport = "grpc"
+	// TransportJob synthesizes the audio via async TTS job.
+	TransportJob Transport = "job"
+)
+
+func (t Transport) String() string {
+	return string(t)
+}
+
+// SynthesisRequest is the transport agnostic speech synthesis request.
+type SynthesisRequest = TTSParams
+
+// SynthesisResult is the result of the speech synthesis.
+type SynthesisResult struct {
+	// Transport is the transport which served the request.
+	Transport Transport
+	// Format is the audio format.
+	// If the request format is not set, it's the transport default:
+	// Raw for TransportGRPC and Mp3 for the HTTP transports.
+	Format OutputFormat
+	// Bytes is the number of audio bytes written.
+	Bytes int64
+	// Duration is the audio duration.
+	// It's only set if the transport reports it.
+	Duration time.Duration
+	// Started is the time the synthesis started.
+	Started time.Time
+	// FirstByte is the time elapsed until the first audio byte was written.
+	FirstByte time.Duration
+	// Elapsed is the total synthesis time.
+	Elapsed time.Duration
+}
+
+// Synthesizer synthesizes speech.
+type Synthesizer interface {
+	// Synthesize synthesizes the speech for req and writes the audio into w.
+	// If the synthesis fails, the returned result reports the bytes written so far.
+	Synthesize(ctx context.Context, req *SynthesisRequest, w io.Writer) (*SynthesisResult, error)
+}
+
+// HTTPSynthesizer synthesizes speech via HTTP TTS stream.
+// The request is sent as TTSParams JSON so that all the set
+// parameters, including Other, are passed to the API.
+type HTTPSynthesizer struct {
+	client *Client
+}
+
+// NewHTTPSynthesizer creates a new HTTP stream synthesizer and returns it.
+func NewHTTPSynthesizer(c *Client) *HTTPSynthesizer {
+	return &HTTPSynthesizer{
+		client: c,
+	}
+}
+
+// Synthesize implements Synthesizer.
+func (s *HTTPSynthesizer) Synthesize(ctx context.Context, req *SynthesisRequest, w io.Writer) (*SynthesisResult, error) {
+	return synthesize(TransportHTTP, req, w, func(w io.Writer) (time.Duration, error) {
+		r, err := s.client.ttsStreamReader(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+
+		_, err = io.Copy(w, r)
+		return 0, err
+	})
+}
+
+// GrpcSynthesizer synthesizes speech via TTSGrpcStream.
+// The stream lease is obtained from the client LeaseManager.
+type GrpcSynthesizer struct {
+	client *Client
+	opts   []GrpcStreamOption
+}
+
+// NewGrpcSynthesizer creates a new gRPC stream synthesizer and returns it.
+// The opts are applied to every stream.
+func NewGrpcSynthesizer(c *Client, opts ...GrpcStreamOption) *GrpcSynthesizer {
+	return &GrpcSynthesizer{
+		client: c,
+		opts:   opts,
+	}
+}
+
+// Synthesize implements Synthesizer.
+func (s *GrpcSynthesizer) Synthesize(ctx context.Context, req *SynthesisRequest, w io.Writer) (*SynthesisResult, error) {
+	return synthesize(TransportGRPC, req, w, func(w io.Writer) (time.Duration, error) {
+		params, err := ToPbParams(req)
+		if err != nil {
+			return 0, err
+		}
+		return 0, s.client.TTSGrpcStream(ctx, w, &pb.TtsRequest{Params: params}, s.opts...)
+	})
+}
+
+// JobSynthesizerOptions configure the TTS job synthesizer.
+type JobSynthesizerOptions struct {
+	// WaitOptions are used when waiting for the job to complete.
+	WaitOptions []WaitOption
+	// DownloadOptions are used when downloading the job audio.
+	DownloadOptions []DownloadOption
+}
+
+// JobSynthesizerOption is a TTS job synthesizer functional option.
+type JobSynthesizerOption func(*JobSynthesizerOptions)
+
+// WithWaitOptions sets the options used when waiting for the job to complete.
+func WithWaitOptions(opts ...WaitOption) JobSynthesizerOption {
+	return func(o *JobSynthesizerOptions) {
+		o.WaitOptions = opts
+	}
+}
+
+// WithDownloadOptions sets the options used when downloading the job audio.
+func WithDownloadOptions(opts ...DownloadOption) JobSynthesizerOption {
+	return func(o *JobSynthesizerOptions) {
+		o.DownloadOptions = opts
+	}
+}
+
+// JobSynthesizer synthesizes speech via async TTS job.
+// It creates the job, waits for it to complete and downloads its audio.
+type JobSynthesizer struct {
+	client *Client
+	opts   JobSynthesizerOptions
+}
+
+// NewJobSynthesizer creates a new TTS job synthesizer and returns it.
+func NewJobSynthesizer(c *Client, opts ...JobSynthesizerOption) *JobSynthesizer {
+	options := JobSynthesizerOptions{}
+	for _, apply := range opts {
+		apply(&options)
+	}
+	return &JobSynthesizer{
+		client: c,
+		opts:   options,
+	}
+}
+
+// Synthesize implements Synthesizer.
+func (s *JobSynthesizer) Synthesize(ctx context.Context, req *SynthesisRequest, w io.Writer) (*SynthesisResult, error) {
+	return synthesize(TransportJob, req, w, func(w io.Writer) (time.Duration, error) {
+		jobReq, err := req.JobReq()
+		if err != nil {
+			return 0, err
+		}
+		job, err := s.client.CreateTTSJob(ctx, jobReq)
+		if err != nil {
+			return 0, err
+		}
+		job, err = s.client.WaitForTTSJob(ctx, job.ID, s.opts.WaitOptions...)
+		if err != nil {
+			return 0, err
+		}
+		duration := time.Duration(job.Output.Duration * float64(time.Second))
+		if _, err := s.client.DownloadTTSJobAudio(ctx, job, w, s.opts.DownloadOptions...); err != nil {
+			return duration, err
+		}
+		return duration, nil
+	})
+}
+
+// synthesize runs fn which writes the audio into w and returns the synthesis result.
+// fn returns the audio duration if the transport reports it.
+func synthesize(transport Transport, req *SynthesisRequest, w io.Writer, fn func(io.Writer) (time.Duration, error)) (*SynthesisResult, error) {
+	format := req.OutputFormat
+	if format == "" {
+		format = Mp3
+		if transport == TransportGRPC {
+			format = Raw
+		}
+	}
+
+	cw := &countingWriter{w: w, started: time.Now()}
+	duration, err := fn(cw)
+
+	return &SynthesisResult{
+		Transport: transport,
+		Format:    format,
+		Bytes:     cw.n,
+		Duration:  duration,
+		Started:   cw.started,
+		FirstByte: cw.firstByte,
+		Elapsed:   time.Since(cw.started),
+	}, err
+}
+
+// countingWriter counts the bytes written and records the first byte latency.
+type countingWriter struct {
+	w         io.Writer
+	n         int64
+	started   time.Time
+	firstByte time.Duration
+}
+
+// Write implements io.Writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 && len(p) > 0 {
+		w.firstByte = time.Since(w.started)
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
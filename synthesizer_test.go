@@ -0,0 +1,100 @@
+package playht
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSynthesizer(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/tts/stream", r.URL.Path)
+		req := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hello", req["text"])
+		assert.Equal(t, "wav", req["output_format"])
+		assert.Equal(t, 0.5, req["top_p"])
+		assert.Equal(t, "value", req["custom"])
+		// unset params must not be sent
+		assert.NotContains(t, req, "speed")
+		assert.NotContains(t, req, "sample_rate")
+		w.Header().Set("Content-Type", "audio/wav")
+		fmt.Fprint(w, "audio")
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	defer c.Close()
+
+	var s Synthesizer = NewHTTPSynthesizer(c)
+	buf := &bytes.Buffer{}
+	topP := float32(0.5)
+	req := &SynthesisRequest{
+		Text:         "hello",
+		OutputFormat: Wav,
+		TopP:         &topP,
+		Other:        map[string]any{"custom": "value"},
+	}
+	res, err := s.Synthesize(context.Background(), req, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "audio", buf.String())
+	assert.Equal(t, TransportHTTP, res.Transport)
+	assert.Equal(t, Wav, res.Format)
+	assert.Equal(t, int64(5), res.Bytes)
+	assert.False(t, res.Started.IsZero())
+	assert.LessOrEqual(t, res.FirstByte, res.Elapsed)
+}
+
+func TestJobSynthesizer(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/tts":
+			fmt.Fprint(w, `{"id":"job","status":"pending"}`)
+		case "/v2/tts/job":
+			fmt.Fprintf(w, `{"id":"job","status":"complete","output":{"url":"%s/audio.mp3","size":5,"duration":1.5}}`, srv.URL)
+		case "/audio.mp3":
+			fmt.Fprint(w, "audio")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	defer c.Close()
+
+	s := NewJobSynthesizer(c, WithWaitOptions(WithProgressStream(false), WithPollInterval(time.Millisecond, time.Millisecond)))
+	buf := &bytes.Buffer{}
+	res, err := s.Synthesize(context.Background(), &SynthesisRequest{Text: "hello"}, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "audio", buf.String())
+	assert.Equal(t, TransportJob, res.Transport)
+	assert.Equal(t, Mp3, res.Format)
+	assert.Equal(t, int64(5), res.Bytes)
+	assert.Equal(t, 1500*time.Millisecond, res.Duration)
+}
+
+func TestGrpcSynthesizerDefaultFormat(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient()
+	defer c.Close()
+
+	res, err := NewGrpcSynthesizer(c).Synthesize(context.Background(), &SynthesisRequest{Text: "hello"}, io.Discard)
+	assert.ErrorIs(t, err, ErrNoGrpcConn)
+	assert.Equal(t, TransportGRPC, res.Transport)
+	assert.Equal(t, Raw, res.Format)
+}
@@ -20,6 +20,9 @@ const (
 	Ogg   OutputFormat = "ogg"
 	Flac  OutputFormat = "flac"
 	Mulaw OutputFormat = "mulaw"
+	// Raw is the model native 32-bit float PCM.
+	// It's only supported over gRPC.
+	Raw OutputFormat = "raw"
 )
 
 func (o OutputFormat) String() string {
@@ -34,6 +37,9 @@ const (
 	Medium  Quality = "medium"
 	High    Quality = "high"
 	Premium Quality = "premium"
+	// Unspecified lets the API pick its default quality.
+	// It's only supported over gRPC.
+	Unspecified Quality = "unspecified"
 )
 
 func (q Quality) String() string {
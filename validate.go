@@ -0,0 +1,207 @@
+package playht
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidRequest is matched by request validation errors.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// FieldError is a request field validation error.
+type FieldError struct {
+	// Field is the JSON name of the invalid field.
+	Field string
+	// Value is the invalid value.
+	Value any
+	// Reason describes why the value is invalid.
+	Reason string
+}
+
+// Error implements error interface.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// ValidationError is returned when the request validation fails.
+// It contains all the invalid request fields.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Is allows matching ValidationError against ErrInvalidRequest via errors.Is.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// Field returns the validation error of the given field.
+func (e *ValidationError) Field(name string) (FieldError, bool) {
+	for _, f := range e.Fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return FieldError{}, false
+}
+
+// validator collects the field validation errors.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) fail(field string, value any, format string, args ...any) {
+	v.fields = append(v.fields, FieldError{
+		Field:  field,
+		Value:  value,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) checkRange(field string, value *float32, r ParamRange) {
+	if value != nil && !r.Contains(*value) {
+		v.fail(field, *value, "must be in range [%g, %g]", r.Min, r.Max)
+	}
+}
+
+// checkOptionalRange checks value is in range r.
+// A nil r means the parameter is not supported by the engine.
+func (v *validator) checkOptionalRange(field string, value *float32, r *ParamRange, engine VoiceEngine) {
+	if value == nil {
+		return
+	}
+	if r == nil {
+		v.fail(field, *value, "not supported by %s", engine)
+		return
+	}
+	v.checkRange(field, value, *r)
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate validates the params against the VoiceEngine capabilities.
+// If VoiceEngine is not set only the engine independent checks are done.
+// It returns *ValidationError which lists all the invalid fields.
+func (p *TTSParams) Validate() error {
+	v := &validator{}
+
+	if strings.TrimSpace(p.Text) == "" {
+		v.fail("text", p.Text, "must not be empty")
+	}
+	if strings.TrimSpace(p.Voice) == "" {
+		v.fail("voice", p.Voice, "must not be empty")
+	}
+	if p.Quality != "" && !slices.Contains(allQualities, p.Quality) {
+		v.fail("quality", p.Quality, "unknown quality")
+	}
+	if p.OutputFormat != "" && !slices.Contains(allFormats, p.OutputFormat) {
+		v.fail("output_format", p.OutputFormat, "unknown format")
+	}
+	if p.Emotion != "" && !slices.Contains(allEmotions, p.Emotion) {
+		v.fail("emotion", p.Emotion, "unknown emotion")
+	}
+
+	caps := anyCapabilities
+	if p.VoiceEngine != "" {
+		var ok bool
+		caps, ok = capabilities[p.VoiceEngine]
+		if !ok {
+			v.fail("voice_engine", p.VoiceEngine, "unknown voice engine")
+			return v.err()
+		}
+		if p.Quality != "" && !slices.Contains(caps.Qualities, p.Quality) {
+			v.fail("quality", p.Quality, "not supported by %s", p.VoiceEngine)
+		}
+		if p.OutputFormat != "" && !slices.Contains(caps.Formats, p.OutputFormat) {
+			v.fail("output_format", p.OutputFormat, "not supported by %s", p.VoiceEngine)
+		}
+		if p.Emotion != "" && !slices.Contains(caps.Emotions, p.Emotion) {
+			v.fail("emotion", p.Emotion, "not supported by %s", p.VoiceEngine)
+		}
+	}
+
+	if p.SampleRate != nil {
+		if rates := caps.SampleRatesFor(p.OutputFormat); !slices.Contains(rates, *p.SampleRate) {
+			format := p.OutputFormat
+			if format == "" {
+				format = Mp3
+			}
+			v.fail("sample_rate", *p.SampleRate, "%s format supports sample rates %v", format, rates)
+		}
+	}
+	if p.Seed != nil && *p.Seed < 0 {
+		v.fail("seed", *p.Seed, "must not be negative")
+	}
+
+	v.checkRange("speed", p.Speed, caps.Speed)
+	v.checkRange("temperature", p.Temperature, caps.Temperature)
+	v.checkRange("top_p", p.TopP, caps.TopP)
+	v.checkOptionalRange("voice_guidance", p.VoiceGuidance, caps.VoiceGuidance, p.VoiceEngine)
+	v.checkOptionalRange("style_guidance", p.StyleGuidance, caps.StyleGuidance, p.VoiceEngine)
+	v.checkOptionalRange("text_guidance", p.TextGuidance, caps.TextGuidance, p.VoiceEngine)
+
+	return v.err()
+}
+
+// Validate validates the TTS stream request.
+// Besides the checks done by TTSParams.Validate,
+// it rejects the formats and qualities only supported over gRPC.
+func (r *CreateTTSStreamReq) Validate() error {
+	return withFieldErrors(StreamReqParams(r).Validate(), httpFieldErrors(r.Quality, r.OutputFormat)...)
+}
+
+// Validate validates the TTS job request.
+// Besides the checks done by TTSParams.Validate, it checks the VoiceEngine
+// supports TTS jobs and rejects the formats and qualities only supported over gRPC.
+func (r *CreateTTSJobReq) Validate() error {
+	fieldErrs := httpFieldErrors(r.Quality, r.OutputFormat)
+	if caps, ok := capabilities[r.VoiceEngine]; ok && !caps.Jobs {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:  "voice_engine",
+			Value:  r.VoiceEngine,
+			Reason: "TTS jobs not supported",
+		})
+	}
+	return withFieldErrors(JobReqParams(r).Validate(), fieldErrs...)
+}
+
+// httpFieldErrors returns the errors of the quality and format
+// which are only supported over gRPC.
+func httpFieldErrors(quality Quality, format OutputFormat) []FieldError {
+	var fieldErrs []FieldError
+	if quality == Unspecified {
+		fieldErrs = append(fieldErrs, FieldError{Field: "quality", Value: quality, Reason: "only supported over gRPC"})
+	}
+	if format == Raw {
+		fieldErrs = append(fieldErrs, FieldError{Field: "output_format", Value: format, Reason: "only supported over gRPC"})
+	}
+	return fieldErrs
+}
+
+// withFieldErrors appends fieldErrs to the validation error err.
+func withFieldErrors(err error, fieldErrs ...FieldError) error {
+	if len(fieldErrs) == 0 {
+		return err
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Fields = append(validationErr.Fields, fieldErrs...)
+		return validationErr
+	}
+	return &ValidationError{Fields: fieldErrs}
+}
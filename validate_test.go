@@ -0,0 +1,112 @@
+package playht
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTSParamsValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		params *TTSParams
+		fields []string
+	}{
+		{
+			name:   "valid",
+			params: &TTSParams{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv2, Emotion: FemaleHappy, Speed: Float32Ptr(1)},
+		},
+		{
+			name:   "valid mulaw",
+			params: &TTSParams{Text: "hello", Voice: "voice", OutputFormat: Mulaw, SampleRate: Int32Ptr(8000)},
+		},
+		{
+			name:   "empty voice",
+			params: &TTSParams{Text: "hello"},
+			fields: []string{"voice"},
+		},
+		{
+			name:   "turbo emotion",
+			params: &TTSParams{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv2Turbo, Emotion: MaleSad},
+			fields: []string{"emotion"},
+		},
+		{
+			name:   "mulaw sample rate",
+			params: &TTSParams{Text: "hello", Voice: "voice", OutputFormat: Mulaw, SampleRate: Int32Ptr(44100)},
+			fields: []string{"sample_rate"},
+		},
+		{
+			name:   "speed",
+			params: &TTSParams{Text: "hello", Voice: "voice", Speed: Float32Ptr(10)},
+			fields: []string{"speed"},
+		},
+		{
+			name:   "v1 guidance",
+			params: &TTSParams{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv1, StyleGuidance: Float32Ptr(5)},
+			fields: []string{"style_guidance"},
+		},
+		{
+			name:   "v1 grpc only format",
+			params: &TTSParams{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv1, OutputFormat: Raw, Quality: Unspecified},
+			fields: []string{"quality", "output_format"},
+		},
+		{
+			name:   "unknown engine",
+			params: &TTSParams{Text: "hello", Voice: "voice", VoiceEngine: "foo"},
+			fields: []string{"voice_engine"},
+		},
+		{
+			name: "multiple",
+			params: &TTSParams{
+				VoiceEngine:  PlayHTv2Turbo,
+				Emotion:      FemaleSad,
+				OutputFormat: Mulaw,
+				SampleRate:   Int32Ptr(44100),
+				Speed:        Float32Ptr(10),
+			},
+			fields: []string{"text", "voice", "emotion", "sample_rate", "speed"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.params.Validate()
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidRequest)
+			var validationErr *ValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				var fields []string
+				for _, f := range validationErr.Fields {
+					fields = append(fields, f.Field)
+				}
+				assert.ElementsMatch(t, tc.fields, fields)
+			}
+		})
+	}
+}
+
+func TestCreateTTSJobReqValidate(t *testing.T) {
+	t.Parallel()
+	err := (&CreateTTSJobReq{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv2Turbo}).Validate()
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	_, ok := validationErr.Field("voice_engine")
+	assert.True(t, ok)
+
+	assert.NoError(t, (&CreateTTSStreamReq{Text: "hello", Voice: "voice", VoiceEngine: PlayHTv2Turbo}).Validate())
+
+	// raw format and unspecified quality are only supported over gRPC
+	err = (&CreateTTSStreamReq{Text: "hello", Voice: "voice", Quality: Unspecified, OutputFormat: Raw}).Validate()
+	assert.ErrorAs(t, err, &validationErr)
+	for _, field := range []string{"quality", "output_format"} {
+		_, ok := validationErr.Field(field)
+		assert.True(t, ok, field)
+	}
+	assert.NoError(t, (&TTSParams{Text: "hello", Voice: "voice", Quality: Unspecified, OutputFormat: Raw}).Validate())
+}
@@ -0,0 +1,133 @@
+package playht
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is the default initial TTS job polling interval.
+	DefaultPollInterval = time.Second
+	// DefaultMaxPollInterval is the default maximum TTS job polling interval.
+	DefaultMaxPollInterval = 30 * time.Second
+)
+
+// WaitOptions configure waiting for the TTS job.
+type WaitOptions struct {
+	// ProgressStream enables waiting via the job progress stream.
+	// Polling is used if the stream fails.
+	ProgressStream bool
+	// PollInterval is the initial polling interval.
+	// It doubles after every poll up to MaxPollInterval.
+	PollInterval time.Duration
+	// MaxPollInterval is the maximum polling interval.
+	// If it's not positive, DefaultMaxPollInterval is used.
+	MaxPollInterval time.Duration
+}
+
+// WaitOption is a TTS job wait functional option.
+type WaitOption func(*WaitOptions)
+
+// WithProgressStream enables or disables waiting via the job progress stream.
+func WithProgressStream(enable bool) WaitOption {
+	return func(o *WaitOptions) {
+		o.ProgressStream = enable
+	}
+}
+
+// WithPollInterval sets the initial and maximum TTS job polling interval.
+func WithPollInterval(interval, maxInterval time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.PollInterval = interval
+		o.MaxPollInterval = maxInterval
+	}
+}
+
+// WaitForTTSJob blocks until the TTS job with the given id completes and returns it.
+// It follows the job progress stream and falls back to polling the job with
+// exponential backoff if the stream fails. It returns *JobFailedError if the job fails.
+// Waiting is canceled when ctx is done.
+func (c *Client) WaitForTTSJob(ctx context.Context, id string, opts ...WaitOption) (*TTSJob, error) {
+	options := WaitOptions{
+		ProgressStream:  true,
+		PollInterval:    DefaultPollInterval,
+		MaxPollInterval: DefaultMaxPollInterval,
+	}
+	for _, apply := range opts {
+		apply(&options)
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if options.MaxPollInterval <= 0 {
+		options.MaxPollInterval = DefaultMaxPollInterval
+	}
+
+	if options.ProgressStream {
+		job, err := c.waitForTTSJobStream(ctx, id)
+		if err == nil {
+			return job, nil
+		}
+		var jobErr *JobFailedError
+		if errors.As(err, &jobErr) || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+
+	return c.pollTTSJob(ctx, id, options)
+}
+
+// waitForTTSJobStream waits for the job via its progress stream.
+func (c *Client) waitForTTSJobStream(ctx context.Context, id string) (*TTSJob, error) {
+	for event, err := range c.TTSJobProgressEvents(ctx, id) {
+		if err != nil {
+			return nil, err
+		}
+		switch event.Type {
+		case JobCompleted:
+			return c.GetTTSJob(ctx, id)
+		case JobError:
+			return nil, &JobFailedError{
+				ID:      id,
+				Status:  TTSJobFailed,
+				Message: event.Error,
+			}
+		}
+	}
+	return nil, errors.New("job progress stream ended unexpectedly")
+}
+
+// pollTTSJob polls the job with exponential backoff until it's no longer processed.
+func (c *Client) pollTTSJob(ctx context.Context, id string, options WaitOptions) (*TTSJob, error) {
+	interval := options.PollInterval
+	for {
+		job, err := c.GetTTSJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case job.Status == TTSJobFailed:
+			return nil, &JobFailedError{
+				ID:     id,
+				Status: job.Status,
+			}
+		case job.Status == TTSJobComplete || job.Output.URL != "":
+			return job, nil
+		}
+
+		// NOTE: jitter prevents many waiters polling in lockstep
+		delay := interval/2 + rand.N(interval/2+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		interval *= 2
+		if interval > options.MaxPollInterval {
+			interval = options.MaxPollInterval
+		}
+	}
+}
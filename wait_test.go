@@ -0,0 +1,113 @@
+package playht
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForTTSJob(t *testing.T) {
+	t.Parallel()
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") == "text/event-stream" {
+				fmt.Fprint(w, "event: completed\ndata: {\"id\":\"job\",\"progress\":1,\"url\":\"https://foo/bar.mp3\"}\n\n")
+				return
+			}
+			fmt.Fprint(w, `{"id":"job","status":"complete","output":{"url":"https://foo/bar.mp3"}}`)
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		job, err := c.WaitForTTSJob(context.Background(), "job")
+		assert.NoError(t, err)
+		assert.Equal(t, TTSJobComplete, job.Status)
+		assert.True(t, job.Status.IsTerminal())
+	})
+	t.Run("stream failed job", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, "event: error\ndata: boom\n\n")
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		_, err := c.WaitForTTSJob(context.Background(), "job")
+		var jobErr *JobFailedError
+		assert.ErrorAs(t, err, &jobErr)
+		assert.Equal(t, "boom", jobErr.Message)
+	})
+	t.Run("poll fallback", func(t *testing.T) {
+		t.Parallel()
+		var polls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error_message":"not found","error_id":"NOT_FOUND"}`)
+				return
+			}
+			if polls.Add(1) < 3 {
+				fmt.Fprint(w, `{"id":"job","status":"generating"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id":"job","status":"complete","output":{"url":"https://foo/bar.mp3"}}`)
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		job, err := c.WaitForTTSJob(context.Background(), "job", WithPollInterval(time.Millisecond, 2*time.Millisecond))
+		assert.NoError(t, err)
+		assert.Equal(t, "https://foo/bar.mp3", job.Output.URL)
+		assert.Equal(t, int32(3), polls.Load())
+	})
+	t.Run("context deadline", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"id":"job","status":"pending"}`)
+		}))
+		defer srv.Close()
+
+		c := NewClient(WithBaseURL(srv.URL))
+		defer c.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := c.WaitForTTSJob(ctx, "job", WithProgressStream(false), WithPollInterval(time.Millisecond, time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("non-positive max interval", func(t *testing.T) {
+		t.Parallel()
+		for _, maxInterval := range []time.Duration{0, -time.Second} {
+			var polls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				polls.Add(1)
+				fmt.Fprint(w, `{"id":"job","status":"pending"}`)
+			}))
+
+			c := NewClient(WithBaseURL(srv.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			_, err := c.WaitForTTSJob(ctx, "job", WithProgressStream(false), WithPollInterval(time.Millisecond, maxInterval))
+			cancel()
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			// the interval keeps doubling instead of busy polling
+			assert.Less(t, polls.Load(), int32(20))
+
+			c.Close()
+			srv.Close()
+		}
+	})
+}